@@ -0,0 +1,277 @@
+/*
+ *
+ *
+ * MIT NON-AI License
+ *
+ * Copyright (c) 2022-2024 Aleksei Kotelnikov(gudron2s@gmail.com)
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy of the software and associated documentation files (the "Software"),
+ * to deal in the Software without restriction, including without limitation the rights to use, copy, modify, merge, publish, distribute, sublicense,
+ * and/or sell copies of the Software, and to permit persons to whom the Software is furnished to do so, subject to the following conditions.
+ *
+ * The above copyright notice and this permission notice shall be included in all copies or substantial portions of the Software.
+ *
+ * In addition, the following restrictions apply:
+ *
+ * 1. The Software and any modifications made to it may not be used for the purpose of training or improving machine learning algorithms,
+ * including but not limited to artificial intelligence, natural language processing, or data mining. This condition applies to any derivatives,
+ * modifications, or updates based on the Software code. Any usage of the Software in an AI-training dataset is considered a breach of this License.
+ *
+ * 2. The Software may not be included in any dataset used for training or improving machine learning algorithms,
+ * including but not limited to artificial intelligence, natural language processing, or data mining.
+ *
+ * 3. Any person or organization found to be in violation of these restrictions will be subject to legal action and may be held liable
+ * for any damages resulting from such use.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM,
+ * DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE
+ * OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+ *
+ */
+
+package healthcheck
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+type DependencyGraphFormat string
+
+const (
+	DependencyGraphFormatDOT     DependencyGraphFormat = "dot"
+	DependencyGraphFormatMermaid DependencyGraphFormat = "mermaid"
+)
+
+var (
+	ErrCheckUnitNameDuplicated      = errors.New("healthcheck check unit with same name already registered")
+	ErrCheckUnitDependencyCycle     = errors.New("healthcheck check unit dependencies cycle detected")
+	ErrUnknownDependencyGraphFormat = errors.New("unknown healthcheck dependency graph format")
+)
+
+// dependentProbeUnit - wrapper of probe unit with dependencies on other check units
+type dependentProbeUnit struct {
+	unit         probeService
+	dependencies []string
+}
+
+func (u *dependentProbeUnit) IsHealed(ctx context.Context) bool {
+	return u.unit.IsHealed(ctx)
+}
+
+// Check - call Check function of wrapped unit if wrapped unit implements it.
+// Nil result means result must be built by IsHealed function
+func (u *dependentProbeUnit) Check(ctx context.Context) *CheckResult {
+	checker, isChecker := u.unit.(checkerService)
+	if !isChecker {
+		return nil
+	}
+
+	return checker.Check(ctx)
+}
+
+func (u *dependentProbeUnit) GetName() string {
+	namedUnit, isNamed := u.unit.(namedProbeService)
+	if !isNamed {
+		return ""
+	}
+
+	return namedUnit.GetName()
+}
+
+func (u *dependentProbeUnit) GetDependencies() []string {
+	return u.dependencies
+}
+
+// WithDependencies - wrap probe unit with dependencies on other check units of same probe type, by check unit names.
+// Probe unit must implement GetName function, otherwise default name of unit used - unit_<index>
+func WithDependencies(unit probeService, dependencies ...string) *dependentProbeUnit {
+	return &dependentProbeUnit{
+		unit:         unit,
+		dependencies: dependencies,
+	}
+}
+
+// DependencyNode - check unit and names of check units on which it depends
+type DependencyNode struct {
+	Name         string   `json:"name"`
+	Dependencies []string `json:"dependencies"`
+}
+
+// DependencyGraph - check units dependencies graph of probe
+type DependencyGraph struct {
+	Probe string            `json:"probe"`
+	Nodes []*DependencyNode `json:"nodes"`
+}
+
+// getUnitDependencies - returns dependencies of probe unit, if unit implements dependentProbeService interface
+func getUnitDependencies(unit probeService) []string {
+	dependentUnit, isDependent := unit.(dependentProbeService)
+	if !isDependent {
+		return nil
+	}
+
+	return dependentUnit.GetDependencies()
+}
+
+// findDependencyCycle - returns path of dependencies cycle which contains start check unit, nil if there is no cycle.
+// Graph without start check unit must be acyclic
+func findDependencyCycle(dependencies map[string][]string, start string) []string {
+	visited := make(map[string]bool, len(dependencies))
+
+	var walk func(name string, path []string) []string
+
+	walk = func(name string, path []string) []string {
+		for _, dependency := range dependencies[name] {
+			if dependency == start {
+				return append(path, dependency)
+			}
+
+			if visited[dependency] {
+				continue
+			}
+
+			visited[dependency] = true
+
+			cycle := walk(dependency, append(path, dependency))
+			if cycle != nil {
+				return cycle
+			}
+		}
+
+		return nil
+	}
+
+	return walk(start, []string{start})
+}
+
+// sortUnitsByDependencies - returns check units in execution order - dependencies before dependent units.
+// Registration order of independent units preserved. Graph must be acyclic
+func sortUnitsByDependencies(units []*checkUnit) []*checkUnit {
+	unitsByName := make(map[string]*checkUnit, len(units))
+	for _, unit := range units {
+		unitsByName[unit.GetName()] = unit
+	}
+
+	sorted := make([]*checkUnit, 0, len(units))
+	visited := make(map[string]bool, len(units))
+
+	var visit func(unit *checkUnit)
+
+	visit = func(unit *checkUnit) {
+		if visited[unit.GetName()] {
+			return
+		}
+
+		visited[unit.GetName()] = true
+
+		for _, dependency := range unit.GetDependencies() {
+			dependencyUnit, isExists := unitsByName[dependency]
+			if isExists {
+				visit(dependencyUnit)
+			}
+		}
+
+		sorted = append(sorted, unit)
+	}
+
+	for _, unit := range units {
+		visit(unit)
+	}
+
+	return sorted
+}
+
+// writeDependencyGraph - write dependencies graphs of probes in DOT or Mermaid format
+func writeDependencyGraph(writer io.Writer, format DependencyGraphFormat, graphs []*DependencyGraph) error {
+	bufWriter := bufio.NewWriter(writer)
+
+	switch format {
+	case DependencyGraphFormatDOT:
+		writeDependencyGraphDOT(bufWriter, graphs)
+	case DependencyGraphFormatMermaid:
+		writeDependencyGraphMermaid(bufWriter, graphs)
+	default:
+		return ErrUnknownDependencyGraphFormat
+	}
+
+	return bufWriter.Flush()
+}
+
+func writeDependencyGraphDOT(writer *bufio.Writer, graphs []*DependencyGraph) {
+	_, _ = writer.WriteString("digraph healthcheck {\n")
+
+	for i, graph := range graphs {
+		_, _ = fmt.Fprintf(writer, "  subgraph cluster_%d {\n    label=%s;\n", i, quoteDOT(graph.Probe))
+
+		for _, node := range graph.Nodes {
+			_, _ = fmt.Fprintf(writer, "    %s [label=%s];\n",
+				quoteDOT(makeCheckKey(graph.Probe, node.Name)), quoteDOT(node.Name))
+		}
+
+		_, _ = writer.WriteString("  }\n")
+
+		// edge direction - from dependency to dependent check unit
+		for _, node := range graph.Nodes {
+			for _, dependency := range node.Dependencies {
+				_, _ = fmt.Fprintf(writer, "  %s -> %s;\n",
+					quoteDOT(makeCheckKey(graph.Probe, dependency)), quoteDOT(makeCheckKey(graph.Probe, node.Name)))
+			}
+		}
+	}
+
+	_, _ = writer.WriteString("}\n")
+}
+
+func writeDependencyGraphMermaid(writer *bufio.Writer, graphs []*DependencyGraph) {
+	_, _ = writer.WriteString("flowchart LR\n")
+
+	for i, graph := range graphs {
+		// mermaid node id can't contain arbitrary symbols, so index based ids used
+		nodeIDs := make(map[string]string, len(graph.Nodes))
+		nodeID := func(name string) string {
+			id, isExists := nodeIDs[name]
+			if !isExists {
+				id = fmt.Sprintf("p%d_n%d", i, len(nodeIDs))
+				nodeIDs[name] = id
+			}
+
+			return id
+		}
+
+		_, _ = fmt.Fprintf(writer, "  subgraph p%d [%s]\n", i, quoteMermaid(graph.Probe))
+
+		for _, node := range graph.Nodes {
+			_, _ = fmt.Fprintf(writer, "    %s[%s]\n", nodeID(node.Name), quoteMermaid(node.Name))
+		}
+
+		_, _ = writer.WriteString("  end\n")
+
+		for _, node := range graph.Nodes {
+			for _, dependency := range node.Dependencies {
+				_, isRegistered := nodeIDs[dependency]
+
+				id := nodeID(dependency)
+				if !isRegistered {
+					// dependency on not registered check unit
+					_, _ = fmt.Fprintf(writer, "  %s[%s]\n", id, quoteMermaid(dependency))
+				}
+
+				_, _ = fmt.Fprintf(writer, "  %s --> %s\n", id, nodeID(node.Name))
+			}
+		}
+	}
+}
+
+func quoteDOT(value string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(value) + `"`
+}
+
+func quoteMermaid(value string) string {
+	return `"` + strings.NewReplacer(`"`, "#quot;", "\n", " ").Replace(value) + `"`
+}
@@ -0,0 +1,240 @@
+/*
+ *
+ *
+ * MIT NON-AI License
+ *
+ * Copyright (c) 2022-2024 Aleksei Kotelnikov(gudron2s@gmail.com)
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy of the software and associated documentation files (the "Software"),
+ * to deal in the Software without restriction, including without limitation the rights to use, copy, modify, merge, publish, distribute, sublicense,
+ * and/or sell copies of the Software, and to permit persons to whom the Software is furnished to do so, subject to the following conditions.
+ *
+ * The above copyright notice and this permission notice shall be included in all copies or substantial portions of the Software.
+ *
+ * In addition, the following restrictions apply:
+ *
+ * 1. The Software and any modifications made to it may not be used for the purpose of training or improving machine learning algorithms,
+ * including but not limited to artificial intelligence, natural language processing, or data mining. This condition applies to any derivatives,
+ * modifications, or updates based on the Software code. Any usage of the Software in an AI-training dataset is considered a breach of this License.
+ *
+ * 2. The Software may not be included in any dataset used for training or improving machine learning algorithms,
+ * including but not limited to artificial intelligence, natural language processing, or data mining.
+ *
+ * 3. Any person or organization found to be in violation of these restrictions will be subject to legal action and may be held liable
+ * for any damages resulting from such use.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM,
+ * DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE
+ * OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+ *
+ */
+package healthcheck
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestFindDependencyCycle(t *testing.T) {
+	testCases := []struct {
+		name          string
+		dependencies  map[string][]string
+		start         string
+		expectedCycle []string
+	}{
+		{
+			name:          "self dependency",
+			dependencies:  map[string][]string{"database": {"database"}},
+			start:         "database",
+			expectedCycle: []string{"database", "database"},
+		},
+		{
+			name: "three nodes cycle",
+			dependencies: map[string][]string{
+				"api":      {"cache"},
+				"database": {"api"},
+				"cache":    {"database"},
+			},
+			start:         "api",
+			expectedCycle: []string{"api", "cache", "database", "api"},
+		},
+		{
+			name: "dependency on missing unit",
+			dependencies: map[string][]string{
+				"api": {"database", "missing"},
+			},
+			start:         "api",
+			expectedCycle: nil,
+		},
+		{
+			name: "diamond without cycle",
+			dependencies: map[string][]string{
+				"api":      {"cache", "database"},
+				"cache":    {"database"},
+				"database": nil,
+			},
+			start:         "api",
+			expectedCycle: nil,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			cycle := findDependencyCycle(testCase.dependencies, testCase.start)
+			if strings.Join(cycle, " -> ") != strings.Join(testCase.expectedCycle, " -> ") {
+				t.Fatalf("expected cycle %v, got %v", testCase.expectedCycle, cycle)
+			}
+		})
+	}
+}
+
+func newTestDependencyChecker(t *testing.T, units ...*fakeProbeUnit) *probeChecker {
+	t.Helper()
+
+	//nolint:exhaustruct // defaults
+	checker := newProbeChecker(ProbeNameRediness, newHealthTracer(), &checkUnitParams{HistorySize: 1})
+
+	for _, unit := range units {
+		if err := checker.AddUnit(unit); err != nil {
+			t.Fatalf("unable to add unit %s: %s", unit.name, err)
+		}
+	}
+
+	return checker
+}
+
+func TestProbeChecker_AddUnit_Dependencies(t *testing.T) {
+	testCases := []struct {
+		name          string
+		units         []*fakeProbeUnit
+		expectedError error
+	}{
+		{
+			name:          "self dependency",
+			units:         []*fakeProbeUnit{newFakeProbeUnit("database", CheckStatusPass, "database")},
+			expectedError: ErrCheckUnitDependencyCycle,
+		},
+		{
+			name: "three nodes cycle",
+			units: []*fakeProbeUnit{
+				newFakeProbeUnit("api", CheckStatusPass, "cache"),
+				newFakeProbeUnit("database", CheckStatusPass, "api"),
+				newFakeProbeUnit("cache", CheckStatusPass, "database"),
+			},
+			expectedError: ErrCheckUnitDependencyCycle,
+		},
+		{
+			name: "dependency on missing unit",
+			units: []*fakeProbeUnit{
+				newFakeProbeUnit("api", CheckStatusPass, "missing"),
+			},
+			expectedError: nil,
+		},
+		{
+			name: "duplicated name",
+			units: []*fakeProbeUnit{
+				newFakeProbeUnit("api", CheckStatusPass),
+				newFakeProbeUnit("api", CheckStatusPass),
+			},
+			expectedError: ErrCheckUnitNameDuplicated,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			lastIndex := len(testCase.units) - 1
+			checker := newTestDependencyChecker(t, testCase.units[:lastIndex]...)
+
+			err := checker.AddUnit(testCase.units[lastIndex])
+			if !errors.Is(err, testCase.expectedError) {
+				t.Fatalf("expected error %v, got %v", testCase.expectedError, err)
+			}
+
+			// rejected unit must not be registered
+			expectedCount := len(testCase.units)
+			if testCase.expectedError != nil {
+				expectedCount--
+			}
+
+			report := checker.Run(context.Background())
+			if len(report.Checks) != expectedCount {
+				t.Fatalf("expected %d check units, got %d", expectedCount, len(report.Checks))
+			}
+		})
+	}
+}
+
+func TestProbeChecker_Run_DependenciesOrder(t *testing.T) {
+	checker := newTestDependencyChecker(t,
+		newFakeProbeUnit("metrics", CheckStatusPass),
+		newFakeProbeUnit("api", CheckStatusPass, "cache", "database"),
+		newFakeProbeUnit("cache", CheckStatusPass, "database"),
+		newFakeProbeUnit("database", CheckStatusPass),
+		newFakeProbeUnit("queue", CheckStatusPass),
+	)
+
+	expectedOrder := "metrics database cache api queue"
+
+	for i := 0; i < 3; i++ {
+		report := checker.Run(context.Background())
+
+		names := make([]string, 0, len(report.Checks))
+		for _, result := range report.Checks {
+			names = append(names, result.Name)
+		}
+
+		if strings.Join(names, " ") != expectedOrder {
+			t.Fatalf("expected order %q, got %q", expectedOrder, strings.Join(names, " "))
+		}
+	}
+}
+
+func TestProbeChecker_Run_SkippedDependents(t *testing.T) {
+	database := newFakeProbeUnit("database", CheckStatusFail)
+	cache := newFakeProbeUnit("cache", CheckStatusPass, "database")
+	api := newFakeProbeUnit("api", CheckStatusPass, "cache")
+	queue := newFakeProbeUnit("queue", CheckStatusPass)
+
+	checker := newTestDependencyChecker(t, database, cache, api, queue)
+
+	report := checker.Run(context.Background())
+	if report.Status != CheckStatusFail {
+		t.Fatalf("expected %s probe status, got %s", CheckStatusFail, report.Status)
+	}
+
+	expectedResults := map[string]struct {
+		status CheckStatus
+		error  string
+	}{
+		"database": {status: CheckStatusFail, error: "database fail"},
+		"cache":    {status: CheckStatusSkipped, error: "skipped: dependency database failing"},
+		// skip reason points to root failing dependency
+		"api":   {status: CheckStatusSkipped, error: "skipped: dependency database failing"},
+		"queue": {status: CheckStatusPass, error: ""},
+	}
+
+	for _, result := range report.Checks {
+		expected := expectedResults[result.Name]
+		if result.Status != expected.status || result.Error != expected.error {
+			t.Fatalf("unexpected result of %s: %s %q", result.Name, result.Status, result.Error)
+		}
+	}
+
+	if cache.calls.Load() != 0 || api.calls.Load() != 0 {
+		t.Fatal("skipped check units must not be executed")
+	}
+
+	database.SetStatus(CheckStatusPass)
+
+	report = checker.Run(context.Background())
+	if report.Status != CheckStatusPass {
+		t.Fatalf("expected %s probe status after recovery of dependency, got %s", CheckStatusPass, report.Status)
+	}
+
+	if cache.calls.Load() != 1 || api.calls.Load() != 1 {
+		t.Fatal("dependent check units must be executed after recovery of dependency")
+	}
+}
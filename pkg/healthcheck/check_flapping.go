@@ -0,0 +1,123 @@
+/*
+ *
+ *
+ * MIT NON-AI License
+ *
+ * Copyright (c) 2022-2024 Aleksei Kotelnikov(gudron2s@gmail.com)
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy of the software and associated documentation files (the "Software"),
+ * to deal in the Software without restriction, including without limitation the rights to use, copy, modify, merge, publish, distribute, sublicense,
+ * and/or sell copies of the Software, and to permit persons to whom the Software is furnished to do so, subject to the following conditions.
+ *
+ * The above copyright notice and this permission notice shall be included in all copies or substantial portions of the Software.
+ *
+ * In addition, the following restrictions apply:
+ *
+ * 1. The Software and any modifications made to it may not be used for the purpose of training or improving machine learning algorithms,
+ * including but not limited to artificial intelligence, natural language processing, or data mining. This condition applies to any derivatives,
+ * modifications, or updates based on the Software code. Any usage of the Software in an AI-training dataset is considered a breach of this License.
+ *
+ * 2. The Software may not be included in any dataset used for training or improving machine learning algorithms,
+ * including but not limited to artificial intelligence, natural language processing, or data mining.
+ *
+ * 3. Any person or organization found to be in violation of these restrictions will be subject to legal action and may be held liable
+ * for any damages resulting from such use.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM,
+ * DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE
+ * OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+ *
+ */
+
+package healthcheck
+
+const (
+	flapOldestStateWeight = 0.8
+	flapWeightRange       = 0.4
+	percentMultiplier     = 100
+
+	flappingHoldErrorMessage = "check unit is flapping"
+)
+
+// flapDetector - detects flapping of check unit by Nagios-style percent state change.
+// State changes in sliding window are weighted - recent changes are more important than old ones.
+// Flapping starts when percent state change exceeds high threshold
+// and stops when percent state change falls below low threshold
+type flapDetector struct {
+	statuses []CheckStatus
+	next     int
+	count    int
+
+	highThreshold float64
+	lowThreshold  float64
+
+	isFlapping    bool
+	changePercent float64
+}
+
+// Add - add raw status of check unit execution, returns flapping flag and percent state change
+func (d *flapDetector) Add(status CheckStatus) (bool, float64) {
+	d.statuses[d.next] = status
+	d.next = (d.next + 1) % len(d.statuses)
+
+	if d.count < len(d.statuses) {
+		d.count++
+	}
+
+	d.changePercent = d.calculateChangePercent()
+
+	// flapping detection starts only after whole window filled up
+	if d.count < len(d.statuses) {
+		return d.isFlapping, d.changePercent
+	}
+
+	switch {
+	case !d.isFlapping && d.changePercent >= d.highThreshold:
+		d.isFlapping = true
+	case d.isFlapping && d.changePercent < d.lowThreshold:
+		d.isFlapping = false
+	}
+
+	return d.isFlapping, d.changePercent
+}
+
+func (d *flapDetector) calculateChangePercent() float64 {
+	if d.count < 2 {
+		return 0
+	}
+
+	start := d.next - d.count
+	weightStep := 0.0
+
+	if d.count > 2 {
+		weightStep = flapWeightRange / float64(d.count-2)
+	}
+
+	var weightedChanges float64
+
+	for i := 1; i < d.count; i++ {
+		prevStatus := d.statuses[(start+i-1+len(d.statuses))%len(d.statuses)]
+		status := d.statuses[(start+i+len(d.statuses))%len(d.statuses)]
+
+		if prevStatus != status {
+			weightedChanges += flapOldestStateWeight + float64(i-1)*weightStep
+		}
+	}
+
+	return weightedChanges * percentMultiplier / float64(d.count-1)
+}
+
+func newFlapDetector(windowSize uint, highThreshold, lowThreshold float64) *flapDetector {
+	return &flapDetector{
+		statuses: make([]CheckStatus, windowSize),
+		next:     0,
+		count:    0,
+
+		highThreshold: highThreshold,
+		lowThreshold:  lowThreshold,
+
+		isFlapping:    false,
+		changePercent: 0,
+	}
+}
@@ -0,0 +1,195 @@
+/*
+ *
+ *
+ * MIT NON-AI License
+ *
+ * Copyright (c) 2022-2024 Aleksei Kotelnikov(gudron2s@gmail.com)
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy of the software and associated documentation files (the "Software"),
+ * to deal in the Software without restriction, including without limitation the rights to use, copy, modify, merge, publish, distribute, sublicense,
+ * and/or sell copies of the Software, and to permit persons to whom the Software is furnished to do so, subject to the following conditions.
+ *
+ * The above copyright notice and this permission notice shall be included in all copies or substantial portions of the Software.
+ *
+ * In addition, the following restrictions apply:
+ *
+ * 1. The Software and any modifications made to it may not be used for the purpose of training or improving machine learning algorithms,
+ * including but not limited to artificial intelligence, natural language processing, or data mining. This condition applies to any derivatives,
+ * modifications, or updates based on the Software code. Any usage of the Software in an AI-training dataset is considered a breach of this License.
+ *
+ * 2. The Software may not be included in any dataset used for training or improving machine learning algorithms,
+ * including but not limited to artificial intelligence, natural language processing, or data mining.
+ *
+ * 3. Any person or organization found to be in violation of these restrictions will be subject to legal action and may be held liable
+ * for any damages resulting from such use.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM,
+ * DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE
+ * OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+ *
+ */
+package healthcheck
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestFlapDetector_Add(t *testing.T) {
+	const (
+		pass = CheckStatusPass
+		fail = CheckStatusFail
+	)
+
+	testCases := []struct {
+		name       string
+		windowSize uint
+		statuses   []CheckStatus
+		// expectedFlapping - flapping flag after each added status
+		expectedFlapping []bool
+		// expectedPercent - percent state change after last added status
+		expectedPercent float64
+	}{
+		{
+			name:             "stable series",
+			windowSize:       5,
+			statuses:         []CheckStatus{pass, pass, pass, pass, pass, pass, pass},
+			expectedFlapping: []bool{false, false, false, false, false, false, false},
+			expectedPercent:  0,
+		},
+		{
+			name:             "stable failing series",
+			windowSize:       5,
+			statuses:         []CheckStatus{fail, fail, fail, fail, fail},
+			expectedFlapping: []bool{false, false, false, false, false},
+			expectedPercent:  0,
+		},
+		{
+			name:             "alternating statuses above high threshold",
+			windowSize:       5,
+			statuses:         []CheckStatus{pass, fail, pass, fail, pass},
+			expectedFlapping: []bool{false, false, false, false, true},
+			expectedPercent:  100,
+		},
+		{
+			name:       "recovery only below low threshold",
+			windowSize: 5,
+			statuses:   []CheckStatus{pass, fail, pass, fail, pass, pass, pass, pass},
+			// 70% and 43.3% are below high threshold, but above low threshold - still flapping
+			expectedFlapping: []bool{false, false, false, false, true, true, true, false},
+			expectedPercent:  20,
+		},
+		{
+			name:             "partially filled window",
+			windowSize:       5,
+			statuses:         []CheckStatus{pass, fail, pass},
+			expectedFlapping: []bool{false, false, false},
+			expectedPercent:  100,
+		},
+		{
+			name:             "single status in window",
+			windowSize:       5,
+			statuses:         []CheckStatus{fail},
+			expectedFlapping: []bool{false},
+			expectedPercent:  0,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			detector := newFlapDetector(testCase.windowSize, 50, 25)
+
+			var percent float64
+
+			for i, status := range testCase.statuses {
+				var isFlapping bool
+
+				isFlapping, percent = detector.Add(status)
+				if isFlapping != testCase.expectedFlapping[i] {
+					t.Fatalf("expected flapping %t after %d status, got %t, percent %.2f",
+						testCase.expectedFlapping[i], i+1, isFlapping, percent)
+				}
+			}
+
+			if math.Abs(percent-testCase.expectedPercent) > 0.001 {
+				t.Fatalf("expected percent state change %.2f, got %.2f", testCase.expectedPercent, percent)
+			}
+		})
+	}
+}
+
+func TestCheckUnit_FlappingHoldStatus(t *testing.T) {
+	unit := newFakeProbeUnit("database", CheckStatusPass)
+
+	checkUnit := newCheckUnit(unit, 0, &checkUnitParams{
+		HistorySize:           1,
+		FlappingEnabled:       true,
+		FlappingWindowSize:    3,
+		FlappingHighThreshold: 50,
+		FlappingLowThreshold:  25,
+		FlappingHoldStatus:    CheckStatusFail,
+	})
+
+	var result *CheckResult
+
+	for _, status := range []CheckStatus{CheckStatusPass, CheckStatusFail, CheckStatusPass} {
+		unit.SetStatus(status)
+
+		_, result = checkUnit.Run(context.Background())
+	}
+
+	if !result.Flapping {
+		t.Fatalf("expected flapping check unit, percent %.2f", result.StateChangePercent)
+	}
+
+	if result.Status != CheckStatusFail || result.Error != flappingHoldErrorMessage {
+		t.Fatalf("expected hold status %s, got %s: %s", CheckStatusFail, result.Status, result.Error)
+	}
+}
+
+func newTestFlappingConfig(holdStatus string) *FlappingConfig {
+	return &FlappingConfig{
+		HealthCheckFlappingHoldStatus:    holdStatus,
+		HealthCheckFlappingWindowSize:    defaultFlappingWindowSize,
+		HealthCheckFlappingHighThreshold: defaultFlappingHighThreshold,
+		HealthCheckFlappingLowThreshold:  defaultFlappingLowThreshold,
+		HealthCheckFlappingEnabled:       true,
+	}
+}
+
+func TestFlappingConfig_Prepare(t *testing.T) {
+	testCases := []struct {
+		name          string
+		config        *FlappingConfig
+		expectedError error
+	}{
+		{name: "nil config", config: nil, expectedError: nil},
+		{name: "empty hold status", config: newTestFlappingConfig(""), expectedError: nil},
+		{name: "warn hold status", config: newTestFlappingConfig("warn"), expectedError: nil},
+		{
+			name:          "unknown hold status",
+			config:        newTestFlappingConfig("failed"),
+			expectedError: ErrFlappingHoldStatusInvalid,
+		},
+		{
+			name:          "skipped hold status",
+			config:        newTestFlappingConfig("skipped"),
+			expectedError: ErrFlappingHoldStatusInvalid,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			//nolint:exhaustruct // only flapping config tested
+			cfg := &HealthcheckHTTPConfig{FlappingConfig: testCase.config}
+
+			err := cfg.Prepare()
+			if !errors.Is(err, testCase.expectedError) {
+				t.Fatalf("expected error %v, got %v", testCase.expectedError, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,120 @@
+/*
+ *
+ *
+ * MIT NON-AI License
+ *
+ * Copyright (c) 2022-2024 Aleksei Kotelnikov(gudron2s@gmail.com)
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy of the software and associated documentation files (the "Software"),
+ * to deal in the Software without restriction, including without limitation the rights to use, copy, modify, merge, publish, distribute, sublicense,
+ * and/or sell copies of the Software, and to permit persons to whom the Software is furnished to do so, subject to the following conditions.
+ *
+ * The above copyright notice and this permission notice shall be included in all copies or substantial portions of the Software.
+ *
+ * In addition, the following restrictions apply:
+ *
+ * 1. The Software and any modifications made to it may not be used for the purpose of training or improving machine learning algorithms,
+ * including but not limited to artificial intelligence, natural language processing, or data mining. This condition applies to any derivatives,
+ * modifications, or updates based on the Software code. Any usage of the Software in an AI-training dataset is considered a breach of this License.
+ *
+ * 2. The Software may not be included in any dataset used for training or improving machine learning algorithms,
+ * including but not limited to artificial intelligence, natural language processing, or data mining.
+ *
+ * 3. Any person or organization found to be in violation of these restrictions will be subject to legal action and may be held liable
+ * for any damages resulting from such use.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM,
+ * DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE
+ * OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+ *
+ */
+
+package healthcheck
+
+import (
+	"sync"
+	"time"
+)
+
+// HistoryEntry - short result of check unit execution, stored in history of check unit
+type HistoryEntry struct {
+	Timestamp time.Time     `json:"timestamp"`
+	Status    CheckStatus   `json:"status"`
+	Duration  time.Duration `json:"duration"`
+	Error     string        `json:"error,omitempty"`
+}
+
+// CheckHistory - recent results of check unit, from oldest to newest
+type CheckHistory struct {
+	Name    string          `json:"name"`
+	Results []*HistoryEntry `json:"results"`
+}
+
+// ProbeHistory - recent results of all check units of probe
+type ProbeHistory struct {
+	Probe  string          `json:"probe"`
+	Checks []*CheckHistory `json:"checks"`
+}
+
+// checkHistory - bounded ring buffer of check unit results
+type checkHistory struct {
+	entries []*HistoryEntry
+	next    int
+	count   int
+
+	mu sync.RWMutex
+}
+
+func (h *checkHistory) Add(result *CheckResult) {
+	if len(h.entries) == 0 {
+		return
+	}
+
+	entry := &HistoryEntry{
+		Timestamp: result.Timestamp,
+		Status:    result.Status,
+		Duration:  result.Duration,
+		Error:     result.Error,
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.entries[h.next] = entry
+	h.next = (h.next + 1) % len(h.entries)
+
+	if h.count < len(h.entries) {
+		h.count++
+	}
+}
+
+// Last - returns up to limit last entries, from oldest to newest. All entries returned if limit is not positive
+func (h *checkHistory) Last(limit int) []*HistoryEntry {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	if limit <= 0 || limit > h.count {
+		limit = h.count
+	}
+
+	result := make([]*HistoryEntry, 0, limit)
+	start := h.next - limit
+
+	for i := 0; i < limit; i++ {
+		index := (start + i + len(h.entries)) % len(h.entries)
+		result = append(result, h.entries[index])
+	}
+
+	return result
+}
+
+func newCheckHistory(size uint) *checkHistory {
+	return &checkHistory{
+		entries: make([]*HistoryEntry, size),
+		next:    0,
+		count:   0,
+
+		mu: sync.RWMutex{},
+	}
+}
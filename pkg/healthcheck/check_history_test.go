@@ -0,0 +1,139 @@
+/*
+ *
+ *
+ * MIT NON-AI License
+ *
+ * Copyright (c) 2022-2024 Aleksei Kotelnikov(gudron2s@gmail.com)
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy of the software and associated documentation files (the "Software"),
+ * to deal in the Software without restriction, including without limitation the rights to use, copy, modify, merge, publish, distribute, sublicense,
+ * and/or sell copies of the Software, and to permit persons to whom the Software is furnished to do so, subject to the following conditions.
+ *
+ * The above copyright notice and this permission notice shall be included in all copies or substantial portions of the Software.
+ *
+ * In addition, the following restrictions apply:
+ *
+ * 1. The Software and any modifications made to it may not be used for the purpose of training or improving machine learning algorithms,
+ * including but not limited to artificial intelligence, natural language processing, or data mining. This condition applies to any derivatives,
+ * modifications, or updates based on the Software code. Any usage of the Software in an AI-training dataset is considered a breach of this License.
+ *
+ * 2. The Software may not be included in any dataset used for training or improving machine learning algorithms,
+ * including but not limited to artificial intelligence, natural language processing, or data mining.
+ *
+ * 3. Any person or organization found to be in violation of these restrictions will be subject to legal action and may be held liable
+ * for any damages resulting from such use.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM,
+ * DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE
+ * OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+ *
+ */
+package healthcheck
+
+import (
+	"testing"
+	"time"
+)
+
+func newTestCheckHistory(size uint, count int) *checkHistory {
+	history := newCheckHistory(size)
+
+	for i := 0; i < count; i++ {
+		result := NewCheckResult("database")
+		result.Timestamp = time.Unix(int64(i), 0)
+
+		history.Add(result)
+	}
+
+	return history
+}
+
+func TestCheckHistory_Last(t *testing.T) {
+	testCases := []struct {
+		name  string
+		size  uint
+		count int
+		limit int
+		// expected - unix timestamps of returned entries, from oldest to newest
+		expected []int64
+	}{
+		{
+			name:     "empty history",
+			size:     3,
+			count:    0,
+			limit:    2,
+			expected: []int64{},
+		},
+		{
+			name:     "zero size history",
+			size:     0,
+			count:    5,
+			limit:    2,
+			expected: []int64{},
+		},
+		{
+			name:     "not full buffer",
+			size:     5,
+			count:    3,
+			limit:    2,
+			expected: []int64{1, 2},
+		},
+		{
+			name:     "not full buffer, limit more than count",
+			size:     5,
+			count:    3,
+			limit:    4,
+			expected: []int64{0, 1, 2},
+		},
+		{
+			name:     "wrapped buffer",
+			size:     3,
+			count:    7,
+			limit:    2,
+			expected: []int64{5, 6},
+		},
+		{
+			name:     "wrapped buffer, limit more than size",
+			size:     3,
+			count:    7,
+			limit:    10,
+			expected: []int64{4, 5, 6},
+		},
+		{
+			name:     "zero limit",
+			size:     3,
+			count:    4,
+			limit:    0,
+			expected: []int64{1, 2, 3},
+		},
+		{
+			name:     "negative limit",
+			size:     3,
+			count:    2,
+			limit:    -1,
+			expected: []int64{0, 1},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			entries := newTestCheckHistory(testCase.size, testCase.count).Last(testCase.limit)
+
+			timestamps := make([]int64, 0, len(entries))
+			for _, entry := range entries {
+				timestamps = append(timestamps, entry.Timestamp.Unix())
+			}
+
+			if len(timestamps) != len(testCase.expected) {
+				t.Fatalf("expected entries %v, got %v", testCase.expected, timestamps)
+			}
+
+			for i := range timestamps {
+				if timestamps[i] != testCase.expected[i] {
+					t.Fatalf("expected entries %v, got %v", testCase.expected, timestamps)
+				}
+			}
+		})
+	}
+}
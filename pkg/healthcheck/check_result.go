@@ -0,0 +1,144 @@
+/*
+ *
+ *
+ * MIT NON-AI License
+ *
+ * Copyright (c) 2022-2024 Aleksei Kotelnikov(gudron2s@gmail.com)
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy of the software and associated documentation files (the "Software"),
+ * to deal in the Software without restriction, including without limitation the rights to use, copy, modify, merge, publish, distribute, sublicense,
+ * and/or sell copies of the Software, and to permit persons to whom the Software is furnished to do so, subject to the following conditions.
+ *
+ * The above copyright notice and this permission notice shall be included in all copies or substantial portions of the Software.
+ *
+ * In addition, the following restrictions apply:
+ *
+ * 1. The Software and any modifications made to it may not be used for the purpose of training or improving machine learning algorithms,
+ * including but not limited to artificial intelligence, natural language processing, or data mining. This condition applies to any derivatives,
+ * modifications, or updates based on the Software code. Any usage of the Software in an AI-training dataset is considered a breach of this License.
+ *
+ * 2. The Software may not be included in any dataset used for training or improving machine learning algorithms,
+ * including but not limited to artificial intelligence, natural language processing, or data mining.
+ *
+ * 3. Any person or organization found to be in violation of these restrictions will be subject to legal action and may be held liable
+ * for any damages resulting from such use.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM,
+ * DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE
+ * OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+ *
+ */
+
+package healthcheck
+
+import (
+	"strings"
+	"time"
+)
+
+type CheckStatus string
+
+const (
+	CheckStatusPass CheckStatus = "pass"
+	CheckStatusFail CheckStatus = "fail"
+	// CheckStatusWarn - check unit degraded, but healthy. Warn status doesn't fail probe
+	CheckStatusWarn CheckStatus = "warn"
+	// CheckStatusSkipped - check unit not executed, because one of its dependencies failing
+	CheckStatusSkipped CheckStatus = "skipped"
+)
+
+// CheckResult - result of single check unit execution
+type CheckResult struct {
+	Name                string        `json:"name"`
+	Status              CheckStatus   `json:"status"`
+	Error               string        `json:"error,omitempty"`
+	Timestamp           time.Time     `json:"timestamp"`
+	Duration            time.Duration `json:"duration"`
+	LastSuccess         time.Time     `json:"lastSuccess"`
+	ConsecutiveFailures uint64        `json:"consecutiveFailures"`
+	Flapping            bool          `json:"flapping"`
+	StateChangePercent  float64       `json:"stateChangePercent"`
+	// Observed - values observed by check unit, e.g. block height or replication lag
+	Observed map[string]any `json:"observed,omitempty"`
+	// Checks - results of sub-units of composite check unit
+	Checks []*CheckResult `json:"checks,omitempty"`
+}
+
+func (r *CheckResult) IsHealthy() bool {
+	return r.Status != CheckStatusFail
+}
+
+// setStatusBySubResults - aggregate statuses of sub-results into result status. Fail status more important
+// than warn status, error of result joins errors of failed or warned sub-results
+func (r *CheckResult) setStatusBySubResults() {
+	failures := make([]string, 0)
+	warnings := make([]string, 0)
+
+	for _, subResult := range r.Checks {
+		switch subResult.Status {
+		case CheckStatusFail:
+			failures = append(failures, formatSubResultError(subResult))
+		case CheckStatusWarn:
+			warnings = append(warnings, formatSubResultError(subResult))
+		case CheckStatusPass, CheckStatusSkipped:
+		}
+	}
+
+	switch {
+	case len(failures) > 0:
+		r.Status = CheckStatusFail
+		r.Error = strings.Join(failures, "; ")
+	case len(warnings) > 0:
+		r.Status = CheckStatusWarn
+		r.Error = strings.Join(warnings, "; ")
+	}
+}
+
+func formatSubResultError(subResult *CheckResult) string {
+	if subResult.Error == "" {
+		return subResult.Name
+	}
+
+	return subResult.Name + ": " + subResult.Error
+}
+
+// NewCheckResult - passed check result with empty observed values, for filling by checkers
+func NewCheckResult(name string) *CheckResult {
+	return &CheckResult{
+		Name:                name,
+		Status:              CheckStatusPass,
+		Error:               "",
+		Timestamp:           time.Time{},
+		Duration:            0,
+		LastSuccess:         time.Time{},
+		ConsecutiveFailures: 0,
+		Flapping:            false,
+		StateChangePercent:  0,
+		Observed:            make(map[string]any),
+		Checks:              nil,
+	}
+}
+
+// ProbeReport - verbose report of all check units of probe
+type ProbeReport struct {
+	Probe     string         `json:"probe"`
+	Status    CheckStatus    `json:"status"`
+	Timestamp time.Time      `json:"timestamp"`
+	Duration  time.Duration  `json:"duration"`
+	Checks    []*CheckResult `json:"checks"`
+}
+
+func (r *ProbeReport) IsHealthy() bool {
+	return r.Status != CheckStatusFail
+}
+
+// addCheckStatus - aggregate status of check unit into report status. Fail status more important than warn status
+func (r *ProbeReport) addCheckStatus(status CheckStatus) {
+	switch {
+	case status == CheckStatusFail:
+		r.Status = CheckStatusFail
+	case status == CheckStatusWarn && r.Status != CheckStatusFail:
+		r.Status = CheckStatusWarn
+	}
+}
@@ -0,0 +1,445 @@
+/*
+ *
+ *
+ * MIT NON-AI License
+ *
+ * Copyright (c) 2022-2024 Aleksei Kotelnikov(gudron2s@gmail.com)
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy of the software and associated documentation files (the "Software"),
+ * to deal in the Software without restriction, including without limitation the rights to use, copy, modify, merge, publish, distribute, sublicense,
+ * and/or sell copies of the Software, and to permit persons to whom the Software is furnished to do so, subject to the following conditions.
+ *
+ * The above copyright notice and this permission notice shall be included in all copies or substantial portions of the Software.
+ *
+ * In addition, the following restrictions apply:
+ *
+ * 1. The Software and any modifications made to it may not be used for the purpose of training or improving machine learning algorithms,
+ * including but not limited to artificial intelligence, natural language processing, or data mining. This condition applies to any derivatives,
+ * modifications, or updates based on the Software code. Any usage of the Software in an AI-training dataset is considered a breach of this License.
+ *
+ * 2. The Software may not be included in any dataset used for training or improving machine learning algorithms,
+ * including but not limited to artificial intelligence, natural language processing, or data mining.
+ *
+ * 3. Any person or organization found to be in violation of these restrictions will be subject to legal action and may be held liable
+ * for any damages resulting from such use.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM,
+ * DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE
+ * OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+ *
+ */
+
+package healthcheck
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// checkUnitParams - params of check units state tracking
+type checkUnitParams struct {
+	HistorySize uint
+
+	FlappingEnabled       bool
+	FlappingWindowSize    uint
+	FlappingHighThreshold float64
+	FlappingLowThreshold  float64
+	FlappingHoldStatus    CheckStatus
+}
+
+// checkUnit - wrapper of probe unit, added by AddLivenessProbeUnit, AddRedinessProbeUnit or AddStartupProbeUnit
+type checkUnit struct {
+	name         string
+	unit         probeService
+	dependencies []string
+
+	lastResult   *CheckResult
+	history      *checkHistory
+	flapDetector *flapDetector
+	// status of flapping unit, empty if unit status must not be overridden while flapping
+	flappingHoldStatus CheckStatus
+
+	mu sync.Mutex
+}
+
+func (u *checkUnit) GetName() string {
+	return u.name
+}
+
+// GetDependencies - returns names of check units on which unit depends
+func (u *checkUnit) GetDependencies() []string {
+	return u.dependencies
+}
+
+// GetHistory - returns up to limit last results of unit, from oldest to newest
+func (u *checkUnit) GetHistory(limit int) *CheckHistory {
+	return &CheckHistory{
+		Name:    u.name,
+		Results: u.history.Last(limit),
+	}
+}
+
+// GetLastResult - returns result of last execution of unit, nil if unit not executed yet
+func (u *checkUnit) GetLastResult() *CheckResult {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	return u.lastResult
+}
+
+// Run - execute probe unit, returns previous and current results of unit
+func (u *checkUnit) Run(ctx context.Context) (*CheckResult, *CheckResult) {
+	return u.store(executeProbeUnit(ctx, u.unit, u.name))
+}
+
+// Skip - mark unit as skipped without execution, returns previous and current results of unit
+func (u *checkUnit) Skip(reason string) (*CheckResult, *CheckResult) {
+	result := NewCheckResult(u.name)
+	result.Status = CheckStatusSkipped
+	result.Error = reason
+	result.Timestamp = time.Now()
+
+	return u.store(result)
+}
+
+func (u *checkUnit) store(result *CheckResult) (*CheckResult, *CheckResult) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	prevResult := u.lastResult
+
+	if result.Status == CheckStatusSkipped {
+		// skipped unit keeps failures counter and last success time of previous execution
+		if prevResult != nil {
+			result.ConsecutiveFailures = prevResult.ConsecutiveFailures
+			result.LastSuccess = prevResult.LastSuccess
+		}
+
+		u.lastResult = result
+		u.history.Add(result)
+
+		return prevResult, result
+	}
+
+	if u.flapDetector != nil {
+		result.Flapping, result.StateChangePercent = u.flapDetector.Add(result.Status)
+
+		if result.Flapping && u.flappingHoldStatus != "" {
+			if result.Error == "" && u.flappingHoldStatus == CheckStatusFail {
+				result.Error = flappingHoldErrorMessage
+			}
+
+			result.Status = u.flappingHoldStatus
+		}
+	}
+
+	result.ConsecutiveFailures = 0
+	result.LastSuccess = result.Timestamp
+
+	if !result.IsHealthy() {
+		result.ConsecutiveFailures = 1
+		result.LastSuccess = time.Time{}
+
+		if prevResult != nil {
+			result.ConsecutiveFailures = prevResult.ConsecutiveFailures + 1
+			result.LastSuccess = prevResult.LastSuccess
+		}
+	}
+
+	u.lastResult = result
+	u.history.Add(result)
+
+	return prevResult, result
+}
+
+// executeProbeUnit - execute probe unit by Check function if unit implements it, otherwise by IsHealed function
+func executeProbeUnit(ctx context.Context, unit probeService, name string) *CheckResult {
+	startedAt := time.Now()
+
+	var result *CheckResult
+
+	checker, isChecker := unit.(checkerService)
+	if isChecker {
+		result = checker.Check(ctx)
+	}
+
+	if result == nil {
+		result = NewCheckResult(name)
+
+		if !unit.IsHealed(ctx) {
+			result.Status = CheckStatusFail
+		}
+	}
+
+	result.Name = name
+	result.Timestamp = startedAt
+	result.Duration = time.Since(startedAt)
+
+	return result
+}
+
+// getProbeUnitName - returns name of probe unit if unit implements namedProbeService interface,
+// otherwise default name by index of unit
+func getProbeUnitName(unit probeService, index int) string {
+	namedUnit, isNamed := unit.(namedProbeService)
+	if isNamed && namedUnit.GetName() != "" {
+		return namedUnit.GetName()
+	}
+
+	return fmt.Sprintf("unit_%d", index)
+}
+
+func newCheckUnit(unit probeService, index int, params *checkUnitParams) *checkUnit {
+	name := getProbeUnitName(unit, index)
+
+	var detector *flapDetector
+	if params.FlappingEnabled && params.FlappingWindowSize > 1 {
+		detector = newFlapDetector(params.FlappingWindowSize,
+			params.FlappingHighThreshold, params.FlappingLowThreshold)
+	}
+
+	return &checkUnit{
+		name:         name,
+		unit:         unit,
+		dependencies: getUnitDependencies(unit),
+
+		lastResult:         nil,
+		history:            newCheckHistory(params.HistorySize),
+		flapDetector:       detector,
+		flappingHoldStatus: params.FlappingHoldStatus,
+
+		mu: sync.Mutex{},
+	}
+}
+
+// makeCheckKey - uniq key of check unit across all probes
+func makeCheckKey(probeName, checkName string) string {
+	return probeName + "/" + checkName
+}
+
+// probeChecker - list of check units of one probe type
+type probeChecker struct {
+	probeName  string
+	tracer     *healthTracer
+	unitParams *checkUnitParams
+
+	units     []*checkUnit
+	observers []checkResultObserver
+
+	mu sync.RWMutex
+}
+
+// AddObserver - add observer of check units results. Must be called before probe http-server start
+func (c *probeChecker) AddObserver(observer checkResultObserver) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.observers = append(c.observers, observer)
+}
+
+// AddUnit - add check unit to probe. Units names must be unique, dependencies of units must not form a cycle.
+// Units executed in order of dependencies - dependencies before dependent units
+func (c *probeChecker) AddUnit(unit probeService) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	newUnit := newCheckUnit(unit, len(c.units), c.unitParams)
+
+	dependencies := make(map[string][]string, len(c.units)+1)
+	for _, registeredUnit := range c.units {
+		if registeredUnit.GetName() == newUnit.GetName() {
+			return fmt.Errorf("%w: %s", ErrCheckUnitNameDuplicated, newUnit.GetName())
+		}
+
+		dependencies[registeredUnit.GetName()] = registeredUnit.GetDependencies()
+	}
+
+	dependencies[newUnit.GetName()] = newUnit.GetDependencies()
+
+	cycle := findDependencyCycle(dependencies, newUnit.GetName())
+	if cycle != nil {
+		return fmt.Errorf("%w: %s", ErrCheckUnitDependencyCycle, strings.Join(cycle, " -> "))
+	}
+
+	c.units = sortUnitsByDependencies(append(c.units, newUnit))
+
+	return nil
+}
+
+// checkResultNotification - previous and current result of check unit for notification of observers
+type checkResultNotification struct {
+	prevResult *CheckResult
+	result     *CheckResult
+}
+
+// Run - execute all check units of probe and build probe report.
+// Observers notified after execution of all units, out of lock, so observer can read state of probe checker
+func (c *probeChecker) Run(ctx context.Context) *ProbeReport {
+	c.mu.RLock()
+	observers := c.observers
+	report, notifications := c.runUnits(ctx)
+	c.mu.RUnlock()
+
+	for _, notification := range notifications {
+		for _, observer := range observers {
+			observer.OnCheckResult(c.probeName, notification.prevResult, notification.result)
+		}
+	}
+
+	return report
+}
+
+// runUnits - execute check units in order of dependencies. Must be called under lock of probe checker
+func (c *probeChecker) runUnits(ctx context.Context) (*ProbeReport, []checkResultNotification) {
+	startedAt := time.Now()
+
+	report := &ProbeReport{
+		Probe:     c.probeName,
+		Status:    CheckStatusPass,
+		Timestamp: startedAt,
+		Duration:  0,
+		Checks:    make([]*CheckResult, 0, len(c.units)),
+	}
+
+	results := make(map[string]*CheckResult, len(c.units))
+	notifications := make([]checkResultNotification, 0, len(c.units))
+
+	for _, unit := range c.units {
+		unitCtx, span := c.tracer.StartCheckSpan(ctx, c.probeName, unit.GetName())
+
+		var prevResult, result *CheckResult
+
+		skipReason := makeSkipReason(unit, results)
+		if skipReason != "" {
+			prevResult, result = unit.Skip(skipReason)
+		} else {
+			prevResult, result = unit.Run(unitCtx)
+		}
+
+		results[unit.GetName()] = result
+
+		span.End(result)
+
+		report.addCheckStatus(result.Status)
+		report.Checks = append(report.Checks, result)
+
+		notifications = append(notifications, checkResultNotification{
+			prevResult: prevResult,
+			result:     result,
+		})
+	}
+
+	report.Duration = time.Since(startedAt)
+
+	return report, notifications
+}
+
+// Snapshot - build probe report by last results of check units, without units execution.
+// Not executed yet units are not included in report
+func (c *probeChecker) Snapshot() *ProbeReport {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	report := &ProbeReport{
+		Probe:     c.probeName,
+		Status:    CheckStatusPass,
+		Timestamp: time.Time{},
+		Duration:  0,
+		Checks:    make([]*CheckResult, 0, len(c.units)),
+	}
+
+	for _, unit := range c.units {
+		result := unit.GetLastResult()
+		if result == nil {
+			continue
+		}
+
+		report.addCheckStatus(result.Status)
+
+		if result.Timestamp.After(report.Timestamp) {
+			report.Timestamp = result.Timestamp
+		}
+
+		report.Checks = append(report.Checks, result)
+	}
+
+	return report
+}
+
+// History - returns up to limit last results of check units. Results of all units returned if checkName is empty
+func (c *probeChecker) History(checkName string, limit int) *ProbeHistory {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	history := &ProbeHistory{
+		Probe:  c.probeName,
+		Checks: make([]*CheckHistory, 0, len(c.units)),
+	}
+
+	for _, unit := range c.units {
+		if checkName != "" && unit.GetName() != checkName {
+			continue
+		}
+
+		history.Checks = append(history.Checks, unit.GetHistory(limit))
+	}
+
+	return history
+}
+
+// Graph - returns check units dependencies graph of probe
+func (c *probeChecker) Graph() *DependencyGraph {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	graph := &DependencyGraph{
+		Probe: c.probeName,
+		Nodes: make([]*DependencyNode, 0, len(c.units)),
+	}
+
+	for _, unit := range c.units {
+		graph.Nodes = append(graph.Nodes, &DependencyNode{
+			Name:         unit.GetName(),
+			Dependencies: unit.GetDependencies(),
+		})
+	}
+
+	return graph
+}
+
+// makeSkipReason - returns reason of unit skip if one of unit dependencies failing or skipped.
+// Empty reason means unit must be executed. Dependencies on not registered units are ignored
+func makeSkipReason(unit *checkUnit, results map[string]*CheckResult) string {
+	for _, dependency := range unit.GetDependencies() {
+		result, isExists := results[dependency]
+		if !isExists {
+			continue
+		}
+
+		switch result.Status {
+		case CheckStatusFail:
+			return fmt.Sprintf("skipped: dependency %s failing", dependency)
+		case CheckStatusSkipped:
+			// skip reason of dependency points to root failing dependency
+			return result.Error
+		case CheckStatusPass:
+		}
+	}
+
+	return ""
+}
+
+func newProbeChecker(probeName string, tracer *healthTracer, unitParams *checkUnitParams) *probeChecker {
+	return &probeChecker{
+		probeName:  probeName,
+		tracer:     tracer,
+		unitParams: unitParams,
+		units:      nil,
+		observers:  nil,
+		mu:         sync.RWMutex{},
+	}
+}
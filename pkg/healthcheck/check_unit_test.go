@@ -0,0 +1,189 @@
+/*
+ *
+ *
+ * MIT NON-AI License
+ *
+ * Copyright (c) 2022-2024 Aleksei Kotelnikov(gudron2s@gmail.com)
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy of the software and associated documentation files (the "Software"),
+ * to deal in the Software without restriction, including without limitation the rights to use, copy, modify, merge, publish, distribute, sublicense,
+ * and/or sell copies of the Software, and to permit persons to whom the Software is furnished to do so, subject to the following conditions.
+ *
+ * The above copyright notice and this permission notice shall be included in all copies or substantial portions of the Software.
+ *
+ * In addition, the following restrictions apply:
+ *
+ * 1. The Software and any modifications made to it may not be used for the purpose of training or improving machine learning algorithms,
+ * including but not limited to artificial intelligence, natural language processing, or data mining. This condition applies to any derivatives,
+ * modifications, or updates based on the Software code. Any usage of the Software in an AI-training dataset is considered a breach of this License.
+ *
+ * 2. The Software may not be included in any dataset used for training or improving machine learning algorithms,
+ * including but not limited to artificial intelligence, natural language processing, or data mining.
+ *
+ * 3. Any person or organization found to be in violation of these restrictions will be subject to legal action and may be held liable
+ * for any damages resulting from such use.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM,
+ * DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE
+ * OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+ *
+ */
+package healthcheck
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestProbeChecker_ConcurrentRunAndAddUnit(t *testing.T) {
+	//nolint:exhaustruct // defaults
+	checker := newProbeChecker(ProbeNameLiveness, newHealthTracer(), &checkUnitParams{HistorySize: 8})
+
+	// history dumper reads history of probe checker on each failure of check unit
+	checker.AddObserver(newHistoryDumper(newDiscardLogger(), 4, checker.History))
+
+	unit := newFakeProbeUnit("database", CheckStatusPass)
+	if err := checker.AddUnit(unit); err != nil {
+		t.Fatalf("unable to add unit: %s", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+
+		wg := sync.WaitGroup{}
+
+		wg.Add(2)
+
+		go func() {
+			defer wg.Done()
+
+			for i := 0; i < 200; i++ {
+				// every second execution of unit is failed, so history dumped on every second run
+				if i%2 == 0 {
+					unit.SetStatus(CheckStatusFail)
+				} else {
+					unit.SetStatus(CheckStatusPass)
+				}
+
+				checker.Run(ctx)
+			}
+		}()
+
+		go func() {
+			defer wg.Done()
+
+			for i := 0; i < 50; i++ {
+				if err := checker.AddUnit(newFakeProbeUnit(fmt.Sprintf("unit_%d", i), CheckStatusFail)); err != nil {
+					t.Errorf("unable to add unit: %s", err)
+				}
+			}
+		}()
+
+		wg.Wait()
+	}()
+
+	select {
+	case <-done:
+	case <-ctx.Done():
+		t.Fatal("deadlock of concurrent run and add of check units")
+	}
+
+	report := checker.Run(context.Background())
+	if len(report.Checks) != 51 {
+		t.Fatalf("expected 51 check units in report, got %d", len(report.Checks))
+	}
+}
+
+// addingUnitObserver - adds check unit from other goroutine and reads history of probe checker
+// while adding of unit waits for lock of probe checker
+type addingUnitObserver struct {
+	checker *probeChecker
+
+	addErrCh chan error
+	once     sync.Once
+}
+
+func (o *addingUnitObserver) OnCheckResult(_ string, _, result *CheckResult) {
+	o.once.Do(func() {
+		go func() {
+			o.addErrCh <- o.checker.AddUnit(newFakeProbeUnit("cache", CheckStatusPass))
+		}()
+
+		// time for adding of unit to start waiting for lock
+		time.Sleep(50 * time.Millisecond)
+
+		o.checker.History(result.Name, 1)
+	})
+}
+
+func TestProbeChecker_ObserverReadsHistoryWhileAddUnit(t *testing.T) {
+	//nolint:exhaustruct // defaults
+	checker := newProbeChecker(ProbeNameLiveness, newHealthTracer(), &checkUnitParams{HistorySize: 1})
+
+	observer := &addingUnitObserver{checker: checker, addErrCh: make(chan error, 1), once: sync.Once{}}
+	checker.AddObserver(observer)
+
+	if err := checker.AddUnit(newFakeProbeUnit("database", CheckStatusFail)); err != nil {
+		t.Fatalf("unable to add unit: %s", err)
+	}
+
+	runDone := make(chan struct{})
+
+	go func() {
+		defer close(runDone)
+
+		checker.Run(context.Background())
+	}()
+
+	select {
+	case <-runDone:
+	case <-time.After(5 * time.Second):
+		t.Fatal("deadlock of observer which reads history while unit adding")
+	}
+
+	if err := <-observer.addErrCh; err != nil {
+		t.Fatalf("unable to add unit: %s", err)
+	}
+}
+
+// recordingObserver - records names of results passed to observer
+type recordingObserver struct {
+	checker *probeChecker
+	names   []string
+}
+
+func (o *recordingObserver) OnCheckResult(_ string, _, result *CheckResult) {
+	// observer must be able to read state of probe checker
+	o.checker.Snapshot()
+
+	o.names = append(o.names, result.Name)
+}
+
+func TestProbeChecker_Run_Observers(t *testing.T) {
+	//nolint:exhaustruct // defaults
+	checker := newProbeChecker(ProbeNameLiveness, newHealthTracer(), &checkUnitParams{HistorySize: 1})
+
+	observer := &recordingObserver{checker: checker, names: nil}
+	checker.AddObserver(observer)
+
+	for _, name := range []string{"database", "cache"} {
+		if err := checker.AddUnit(newFakeProbeUnit(name, CheckStatusPass)); err != nil {
+			t.Fatalf("unable to add unit: %s", err)
+		}
+	}
+
+	checker.Run(context.Background())
+
+	if len(observer.names) != 2 || observer.names[0] != "database" || observer.names[1] != "cache" {
+		t.Fatalf("unexpected notifications of observer: %v", observer.names)
+	}
+}
@@ -0,0 +1,122 @@
+/*
+ *
+ *
+ * MIT NON-AI License
+ *
+ * Copyright (c) 2022-2024 Aleksei Kotelnikov(gudron2s@gmail.com)
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy of the software and associated documentation files (the "Software"),
+ * to deal in the Software without restriction, including without limitation the rights to use, copy, modify, merge, publish, distribute, sublicense,
+ * and/or sell copies of the Software, and to permit persons to whom the Software is furnished to do so, subject to the following conditions.
+ *
+ * The above copyright notice and this permission notice shall be included in all copies or substantial portions of the Software.
+ *
+ * In addition, the following restrictions apply:
+ *
+ * 1. The Software and any modifications made to it may not be used for the purpose of training or improving machine learning algorithms,
+ * including but not limited to artificial intelligence, natural language processing, or data mining. This condition applies to any derivatives,
+ * modifications, or updates based on the Software code. Any usage of the Software in an AI-training dataset is considered a breach of this License.
+ *
+ * 2. The Software may not be included in any dataset used for training or improving machine learning algorithms,
+ * including but not limited to artificial intelligence, natural language processing, or data mining.
+ *
+ * 3. Any person or organization found to be in violation of these restrictions will be subject to legal action and may be held liable
+ * for any damages resulting from such use.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM,
+ * DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE
+ * OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+ *
+ */
+
+package healthcheck
+
+import (
+	"context"
+)
+
+// bitcoinNodeChecker - probe unit of bitcoin-like node sync status. Uses getblockchaininfo
+// and getconnectioncount json-rpc methods
+type bitcoinNodeChecker struct {
+	CheckerBase
+
+	client  *nodeAPIClient
+	options BlockchainNodeCheckerOptions
+}
+
+func (c *bitcoinNodeChecker) Check(ctx context.Context) *CheckResult {
+	result := NewCheckResult(c.name)
+
+	state, chain, err := c.getState(ctx)
+	if err != nil {
+		result.Status = CheckStatusFail
+		result.Error = err.Error()
+
+		return result
+	}
+
+	result.Observed[ObservedChainKey] = chain
+
+	evaluateBlockchainNodeState(state, &c.options, result)
+
+	return result
+}
+
+func (c *bitcoinNodeChecker) getState(ctx context.Context) (*blockchainNodeState, string, error) {
+	ctx, cancel := withNodeCheckTimeout(ctx, c.options.Timeout)
+	defer cancel()
+
+	var info struct {
+		Chain                string  `json:"chain"`
+		Blocks               uint64  `json:"blocks"`
+		Headers              uint64  `json:"headers"`
+		InitialBlockDownload bool    `json:"initialblockdownload"`
+		VerificationProgress float64 `json:"verificationprogress"`
+	}
+
+	err := c.client.Call(ctx, "getblockchaininfo", nil, &info)
+	if err != nil {
+		return nil, "", err
+	}
+
+	var peers uint64
+
+	err = c.client.Call(ctx, "getconnectioncount", nil, &peers)
+	if err != nil {
+		return nil, "", err
+	}
+
+	state := &blockchainNodeState{
+		Height:    info.Blocks,
+		LagBlocks: 0,
+		IsSyncing: info.InitialBlockDownload,
+		Peers:     &peers,
+	}
+
+	if info.Headers > info.Blocks {
+		state.LagBlocks = info.Headers - info.Blocks
+	}
+
+	return state, info.Chain, nil
+}
+
+// NewBitcoinNodeChecker - returns probe unit of bitcoin-like node, e.g. bitcoind, litecoind, dogecoind.
+// Credentials of rpc can be set in options url. Returns error if url not set in options
+func NewBitcoinNodeChecker(name string, options *BlockchainNodeCheckerOptions) (*bitcoinNodeChecker, error) {
+	err := validateBlockchainNodeCheckerOptions(name, options)
+	if err != nil {
+		return nil, err
+	}
+
+	checker := &bitcoinNodeChecker{
+		CheckerBase: CheckerBase{name: name, checkFunc: nil},
+
+		client:  newNodeAPIClient(options),
+		options: *options,
+	}
+
+	checker.checkFunc = checker.Check
+
+	return checker, nil
+}
@@ -0,0 +1,146 @@
+/*
+ *
+ *
+ * MIT NON-AI License
+ *
+ * Copyright (c) 2022-2024 Aleksei Kotelnikov(gudron2s@gmail.com)
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy of the software and associated documentation files (the "Software"),
+ * to deal in the Software without restriction, including without limitation the rights to use, copy, modify, merge, publish, distribute, sublicense,
+ * and/or sell copies of the Software, and to permit persons to whom the Software is furnished to do so, subject to the following conditions.
+ *
+ * The above copyright notice and this permission notice shall be included in all copies or substantial portions of the Software.
+ *
+ * In addition, the following restrictions apply:
+ *
+ * 1. The Software and any modifications made to it may not be used for the purpose of training or improving machine learning algorithms,
+ * including but not limited to artificial intelligence, natural language processing, or data mining. This condition applies to any derivatives,
+ * modifications, or updates based on the Software code. Any usage of the Software in an AI-training dataset is considered a breach of this License.
+ *
+ * 2. The Software may not be included in any dataset used for training or improving machine learning algorithms,
+ * including but not limited to artificial intelligence, natural language processing, or data mining.
+ *
+ * 3. Any person or organization found to be in violation of these restrictions will be subject to legal action and may be held liable
+ * for any damages resulting from such use.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM,
+ * DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE
+ * OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+ *
+ */
+package healthcheck
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestBitcoinNodeChecker(t *testing.T) {
+	testCases := []struct {
+		name           string
+		info           map[string]any
+		peers          uint64
+		options        BlockchainNodeCheckerOptions
+		expectedStatus CheckStatus
+	}{
+		{
+			name:           "synced node",
+			info:           map[string]any{"chain": "main", "blocks": 850000, "headers": 850000},
+			peers:          10,
+			options:        BlockchainNodeCheckerOptions{MinPeers: 3}, //nolint:exhaustruct // only tested options
+			expectedStatus: CheckStatusPass,
+		},
+		{
+			name: "initial block download without lag thresholds",
+			info: map[string]any{
+				"chain": "main", "blocks": 800000, "headers": 850000, "initialblockdownload": true,
+			},
+			peers:          10,
+			options:        BlockchainNodeCheckerOptions{}, //nolint:exhaustruct // defaults
+			expectedStatus: CheckStatusFail,
+		},
+		{
+			name:  "headers ahead of blocks within warn lag",
+			info:  map[string]any{"chain": "main", "blocks": 849990, "headers": 850000},
+			peers: 10,
+			//nolint:exhaustruct // only tested options
+			options:        BlockchainNodeCheckerOptions{WarnLagBlocks: 5, MaxLagBlocks: 100},
+			expectedStatus: CheckStatusWarn,
+		},
+		{
+			name:           "no peers",
+			info:           map[string]any{"chain": "main", "blocks": 850000, "headers": 850000},
+			peers:          0,
+			options:        BlockchainNodeCheckerOptions{MinPeers: 1}, //nolint:exhaustruct // only tested options
+			expectedStatus: CheckStatusFail,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			server := startFakeJSONRPCServer(t, map[string]any{
+				"getblockchaininfo":  testCase.info,
+				"getconnectioncount": testCase.peers,
+			})
+
+			options := testCase.options
+			options.URL = server.URL
+
+			checker, err := NewBitcoinNodeChecker("bitcoin", &options)
+			if err != nil {
+				t.Fatalf("unable to create checker: %s", err)
+			}
+
+			result := checker.Check(context.Background())
+			if result.Status != testCase.expectedStatus {
+				t.Fatalf("unexpected status: %s, expected: %s, error: %s",
+					result.Status, testCase.expectedStatus, result.Error)
+			}
+
+			if result.Observed[ObservedChainKey] != "main" {
+				t.Fatalf("unexpected chain: %v", result.Observed[ObservedChainKey])
+			}
+		})
+	}
+}
+
+func TestBitcoinNodeCheckerBasicAuth(t *testing.T) {
+	rpcServer := startFakeJSONRPCServer(t, map[string]any{
+		"getblockchaininfo":  map[string]any{"chain": "main", "blocks": 850000, "headers": 850000},
+		"getconnectioncount": 10,
+	})
+
+	// bitcoind responds to unauthorized requests with 401 status code and empty body
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, password, isSet := r.BasicAuth()
+		if !isSet || user != "rpc" || password != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+
+			return
+		}
+
+		rpcServer.Config.Handler.ServeHTTP(w, r)
+	}))
+	t.Cleanup(server.Close)
+
+	for password, expectedStatus := range map[string]CheckStatus{
+		"secret": CheckStatusPass,
+		"wrong":  CheckStatusFail,
+	} {
+		checker, err := NewBitcoinNodeChecker("bitcoin", &BlockchainNodeCheckerOptions{ //nolint:exhaustruct // defaults
+			URL: strings.Replace(server.URL, "http://", "http://rpc:"+password+"@", 1),
+		})
+		if err != nil {
+			t.Fatalf("unable to create checker: %s", err)
+		}
+
+		result := checker.Check(context.Background())
+		if result.Status != expectedStatus {
+			t.Fatalf("unexpected status with password %s: %s, error: %s", password, result.Status, result.Error)
+		}
+	}
+}
@@ -0,0 +1,148 @@
+/*
+ *
+ *
+ * MIT NON-AI License
+ *
+ * Copyright (c) 2022-2024 Aleksei Kotelnikov(gudron2s@gmail.com)
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy of the software and associated documentation files (the "Software"),
+ * to deal in the Software without restriction, including without limitation the rights to use, copy, modify, merge, publish, distribute, sublicense,
+ * and/or sell copies of the Software, and to permit persons to whom the Software is furnished to do so, subject to the following conditions.
+ *
+ * The above copyright notice and this permission notice shall be included in all copies or substantial portions of the Software.
+ *
+ * In addition, the following restrictions apply:
+ *
+ * 1. The Software and any modifications made to it may not be used for the purpose of training or improving machine learning algorithms,
+ * including but not limited to artificial intelligence, natural language processing, or data mining. This condition applies to any derivatives,
+ * modifications, or updates based on the Software code. Any usage of the Software in an AI-training dataset is considered a breach of this License.
+ *
+ * 2. The Software may not be included in any dataset used for training or improving machine learning algorithms,
+ * including but not limited to artificial intelligence, natural language processing, or data mining.
+ *
+ * 3. Any person or organization found to be in violation of these restrictions will be subject to legal action and may be held liable
+ * for any damages resulting from such use.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM,
+ * DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE
+ * OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+ *
+ */
+
+package healthcheck
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+const (
+	ObservedHeadTimestampKey = "headTimestamp"
+	ObservedHeadAgeKey       = "headAge"
+)
+
+const (
+	chainHeadDefaultWarnMultiplier = 5
+	chainHeadDefaultFailMultiplier = 10
+)
+
+// ChainHead - latest known block of chain
+type ChainHead struct {
+	Height    uint64
+	Timestamp time.Time
+}
+
+// ChainHeadFreshnessCheckerOptions - options of chain head freshness checker
+type ChainHeadFreshnessCheckerOptions struct {
+	// BlockInterval - expected interval of chain block production, required, e.g. 12s for ethereum, 3s for tron
+	BlockInterval time.Duration
+	// WarnMultiplier - warn if head age exceeds BlockInterval * WarnMultiplier, default - 5
+	WarnMultiplier float64
+	// FailMultiplier - fail if head age exceeds BlockInterval * FailMultiplier, default - 10
+	FailMultiplier float64
+}
+
+// chainHeadFreshnessChecker - probe unit which warns or fails if latest block of chain is too old,
+// e.g. node reports synced status, but stopped receiving new blocks
+type chainHeadFreshnessChecker struct {
+	CheckerBase
+
+	headFunc func(ctx context.Context) (ChainHead, error)
+
+	warnAge time.Duration
+	failAge time.Duration
+}
+
+func (c *chainHeadFreshnessChecker) Check(ctx context.Context) *CheckResult {
+	result := NewCheckResult(c.name)
+
+	head, err := c.headFunc(ctx)
+	if err != nil {
+		result.Status = CheckStatusFail
+		result.Error = fmt.Sprintf("unable to get chain head: %s", err)
+
+		return result
+	}
+
+	headAge := time.Since(head.Timestamp)
+
+	result.Observed[ObservedHeightKey] = head.Height
+	result.Observed[ObservedHeadTimestampKey] = head.Timestamp
+	result.Observed[ObservedHeadAgeKey] = headAge.Truncate(time.Millisecond).String()
+
+	switch {
+	case headAge > c.failAge:
+		result.Status = CheckStatusFail
+		result.Error = fmt.Sprintf("chain head %d is %s old, max age %s",
+			head.Height, headAge.Truncate(time.Millisecond), c.failAge)
+	case headAge > c.warnAge:
+		result.Status = CheckStatusWarn
+		result.Error = fmt.Sprintf("chain head %d is %s old, warn age %s",
+			head.Height, headAge.Truncate(time.Millisecond), c.warnAge)
+	}
+
+	return result
+}
+
+// NewChainHeadFreshnessChecker - probe unit which gets latest block of chain by headFunc from any source,
+// e.g. node api or blocks scanner storage, and compares age of block with multiples of expected block interval.
+// Returns error if headFunc or block interval not set
+func NewChainHeadFreshnessChecker(name string,
+	headFunc func(ctx context.Context) (ChainHead, error),
+	options *ChainHeadFreshnessCheckerOptions,
+) (*chainHeadFreshnessChecker, error) {
+	if headFunc == nil {
+		return nil, fmt.Errorf("%w: head function of chain head freshness checker %s",
+			ErrCheckerOptionRequired, name)
+	}
+
+	if options == nil || options.BlockInterval <= 0 {
+		return nil, fmt.Errorf("%w: block interval of chain head freshness checker %s",
+			ErrCheckerOptionRequired, name)
+	}
+
+	warnMultiplier := options.WarnMultiplier
+	if warnMultiplier <= 0 {
+		warnMultiplier = chainHeadDefaultWarnMultiplier
+	}
+
+	failMultiplier := options.FailMultiplier
+	if failMultiplier <= 0 {
+		failMultiplier = chainHeadDefaultFailMultiplier
+	}
+
+	checker := &chainHeadFreshnessChecker{
+		CheckerBase: CheckerBase{name: name, checkFunc: nil},
+
+		headFunc: headFunc,
+
+		warnAge: time.Duration(float64(options.BlockInterval) * warnMultiplier),
+		failAge: time.Duration(float64(options.BlockInterval) * failMultiplier),
+	}
+
+	checker.checkFunc = checker.Check
+
+	return checker, nil
+}
@@ -0,0 +1,130 @@
+/*
+ *
+ *
+ * MIT NON-AI License
+ *
+ * Copyright (c) 2022-2024 Aleksei Kotelnikov(gudron2s@gmail.com)
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy of the software and associated documentation files (the "Software"),
+ * to deal in the Software without restriction, including without limitation the rights to use, copy, modify, merge, publish, distribute, sublicense,
+ * and/or sell copies of the Software, and to permit persons to whom the Software is furnished to do so, subject to the following conditions.
+ *
+ * The above copyright notice and this permission notice shall be included in all copies or substantial portions of the Software.
+ *
+ * In addition, the following restrictions apply:
+ *
+ * 1. The Software and any modifications made to it may not be used for the purpose of training or improving machine learning algorithms,
+ * including but not limited to artificial intelligence, natural language processing, or data mining. This condition applies to any derivatives,
+ * modifications, or updates based on the Software code. Any usage of the Software in an AI-training dataset is considered a breach of this License.
+ *
+ * 2. The Software may not be included in any dataset used for training or improving machine learning algorithms,
+ * including but not limited to artificial intelligence, natural language processing, or data mining.
+ *
+ * 3. Any person or organization found to be in violation of these restrictions will be subject to legal action and may be held liable
+ * for any damages resulting from such use.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM,
+ * DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE
+ * OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+ *
+ */
+package healthcheck
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestChainHeadFreshnessChecker(t *testing.T) {
+	testCases := []struct {
+		name           string
+		headAge        time.Duration
+		headErr        error
+		options        ChainHeadFreshnessCheckerOptions
+		expectedStatus CheckStatus
+	}{
+		{
+			name:           "fresh head",
+			headAge:        10 * time.Second,
+			headErr:        nil,
+			options:        ChainHeadFreshnessCheckerOptions{BlockInterval: 12 * time.Second}, //nolint:exhaustruct // defaults
+			expectedStatus: CheckStatusPass,
+		},
+		{
+			name:           "head older than default warn multiplier",
+			headAge:        90 * time.Second,
+			headErr:        nil,
+			options:        ChainHeadFreshnessCheckerOptions{BlockInterval: 12 * time.Second}, //nolint:exhaustruct // defaults
+			expectedStatus: CheckStatusWarn,
+		},
+		{
+			name:           "head older than default fail multiplier",
+			headAge:        3 * time.Minute,
+			headErr:        nil,
+			options:        ChainHeadFreshnessCheckerOptions{BlockInterval: 12 * time.Second}, //nolint:exhaustruct // defaults
+			expectedStatus: CheckStatusFail,
+		},
+		{
+			name:    "head older than custom fail multiplier",
+			headAge: 15 * time.Second,
+			headErr: nil,
+			options: ChainHeadFreshnessCheckerOptions{
+				BlockInterval:  3 * time.Second,
+				WarnMultiplier: 2,
+				FailMultiplier: 4,
+			},
+			expectedStatus: CheckStatusFail,
+		},
+		{
+			name:           "head function error",
+			headAge:        0,
+			headErr:        errors.New("node unavailable"),
+			options:        ChainHeadFreshnessCheckerOptions{BlockInterval: 12 * time.Second}, //nolint:exhaustruct // defaults
+			expectedStatus: CheckStatusFail,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			checker, err := NewChainHeadFreshnessChecker("chain_head",
+				func(_ context.Context) (ChainHead, error) {
+					return ChainHead{Height: 100, Timestamp: time.Now().Add(-testCase.headAge)}, testCase.headErr
+				}, &testCase.options)
+			if err != nil {
+				t.Fatalf("unable to create checker: %s", err)
+			}
+
+			result := checker.Check(context.Background())
+			if result.Status != testCase.expectedStatus {
+				t.Fatalf("unexpected status: %s, expected: %s, error: %s",
+					result.Status, testCase.expectedStatus, result.Error)
+			}
+		})
+	}
+}
+
+func TestChainHeadFreshnessCheckerOptionsValidation(t *testing.T) {
+	headFunc := func(_ context.Context) (ChainHead, error) {
+		return ChainHead{Height: 100, Timestamp: time.Now()}, nil
+	}
+
+	_, err := NewChainHeadFreshnessChecker("chain_head", headFunc, nil)
+	if !errors.Is(err, ErrCheckerOptionRequired) {
+		t.Fatalf("unexpected error on nil options: %v", err)
+	}
+
+	//nolint:exhaustruct // block interval not set
+	_, err = NewChainHeadFreshnessChecker("chain_head", headFunc, &ChainHeadFreshnessCheckerOptions{})
+	if !errors.Is(err, ErrCheckerOptionRequired) {
+		t.Fatalf("unexpected error without block interval: %v", err)
+	}
+
+	_, err = NewChainHeadFreshnessChecker("chain_head", nil,
+		&ChainHeadFreshnessCheckerOptions{BlockInterval: time.Second}) //nolint:exhaustruct // defaults
+	if !errors.Is(err, ErrCheckerOptionRequired) {
+		t.Fatalf("unexpected error without head function: %v", err)
+	}
+}
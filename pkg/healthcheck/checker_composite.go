@@ -0,0 +1,179 @@
+/*
+ *
+ *
+ * MIT NON-AI License
+ *
+ * Copyright (c) 2022-2024 Aleksei Kotelnikov(gudron2s@gmail.com)
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy of the software and associated documentation files (the "Software"),
+ * to deal in the Software without restriction, including without limitation the rights to use, copy, modify, merge, publish, distribute, sublicense,
+ * and/or sell copies of the Software, and to permit persons to whom the Software is furnished to do so, subject to the following conditions.
+ *
+ * The above copyright notice and this permission notice shall be included in all copies or substantial portions of the Software.
+ *
+ * In addition, the following restrictions apply:
+ *
+ * 1. The Software and any modifications made to it may not be used for the purpose of training or improving machine learning algorithms,
+ * including but not limited to artificial intelligence, natural language processing, or data mining. This condition applies to any derivatives,
+ * modifications, or updates based on the Software code. Any usage of the Software in an AI-training dataset is considered a breach of this License.
+ *
+ * 2. The Software may not be included in any dataset used for training or improving machine learning algorithms,
+ * including but not limited to artificial intelligence, natural language processing, or data mining.
+ *
+ * 3. Any person or organization found to be in violation of these restrictions will be subject to legal action and may be held liable
+ * for any damages resulting from such use.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM,
+ * DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE
+ * OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+ *
+ */
+
+package healthcheck
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"sync"
+)
+
+var (
+	ErrCompositeUnitsNotSet       = errors.New("sub-units of composite check not set")
+	ErrCompositeCountOutOfRange   = errors.New("required healthy count of composite check out of range")
+	ErrCompositePercentOutOfRange = errors.New("required healthy percent of composite check out of range")
+)
+
+// compositeChecker - probe unit which composes multiple probe units into one check.
+// Sub-units executed concurrently, composite check passes if count of healthy sub-units
+// is not less than required count. Composite check warns if it passes, but one of sub-units warns
+type compositeChecker struct {
+	CheckerBase
+
+	units []probeService
+	// requiredHealthyCount - returns required count of healthy sub-units by total count of sub-units
+	requiredHealthyCount func(total int) int
+}
+
+// Check - execute all sub-units and build composite check result with results of sub-units
+func (c *compositeChecker) Check(ctx context.Context) *CheckResult {
+	subResults := make([]*CheckResult, len(c.units))
+
+	wg := sync.WaitGroup{}
+	for i, unit := range c.units {
+		wg.Add(1)
+
+		go func(index int, unit probeService) {
+			defer wg.Done()
+
+			subResults[index] = executeProbeUnit(ctx, unit, getProbeUnitName(unit, index))
+		}(i, unit)
+	}
+
+	wg.Wait()
+
+	healthyCount := 0
+	failures := make([]string, 0, len(subResults))
+	warnings := make([]string, 0)
+
+	for _, subResult := range subResults {
+		if subResult.Status == CheckStatusWarn {
+			warnings = append(warnings, formatSubResultError(subResult))
+		}
+
+		if subResult.IsHealthy() {
+			healthyCount++
+
+			continue
+		}
+
+		failures = append(failures, formatSubResultError(subResult))
+	}
+
+	result := NewCheckResult(c.name)
+	result.Checks = subResults
+
+	requiredCount := c.requiredHealthyCount(len(subResults))
+
+	switch {
+	case healthyCount < requiredCount:
+		result.Status = CheckStatusFail
+		result.Error = fmt.Sprintf("healthy %d of %d checks, required %d: %s",
+			healthyCount, len(subResults), requiredCount, strings.Join(failures, "; "))
+	case len(warnings) > 0:
+		result.Status = CheckStatusWarn
+		result.Error = fmt.Sprintf("degraded %d of %d checks: %s",
+			len(warnings), len(subResults), strings.Join(warnings, "; "))
+	}
+
+	return result
+}
+
+func newCompositeChecker(name string,
+	requiredHealthyCount func(total int) int,
+	units []probeService,
+) *compositeChecker {
+	checker := &compositeChecker{
+		CheckerBase: CheckerBase{name: name, checkFunc: nil},
+
+		units:                units,
+		requiredHealthyCount: requiredHealthyCount,
+	}
+
+	checker.checkFunc = checker.Check
+
+	return checker
+}
+
+// NewAllChecker - composite probe unit, passes if all sub-units healthy. At least one sub-unit required
+func NewAllChecker(name string, units ...probeService) (*compositeChecker, error) {
+	if len(units) == 0 {
+		return nil, ErrCompositeUnitsNotSet
+	}
+
+	return newCompositeChecker(name, func(total int) int {
+		return total
+	}, units), nil
+}
+
+// NewAnyChecker - composite probe unit, passes if at least one sub-unit healthy. At least one sub-unit required
+func NewAnyChecker(name string, units ...probeService) (*compositeChecker, error) {
+	if len(units) == 0 {
+		return nil, ErrCompositeUnitsNotSet
+	}
+
+	return newCompositeChecker(name, func(_ int) int {
+		return 1
+	}, units), nil
+}
+
+// NewAtLeastChecker - composite probe unit, passes if at least count of sub-units healthy, e.g. quorum of nodes pool.
+// Count must be in range from 1 to count of sub-units
+func NewAtLeastChecker(name string, count uint, units ...probeService) (*compositeChecker, error) {
+	if count == 0 || count > uint(len(units)) {
+		return nil, fmt.Errorf("%w: %d, count of sub-units %d", ErrCompositeCountOutOfRange, count, len(units))
+	}
+
+	return newCompositeChecker(name, func(_ int) int {
+		return int(count)
+	}, units), nil
+}
+
+// NewPercentageChecker - composite probe unit, passes if not less than percent of sub-units healthy.
+// Percent must be greater than 0 and not greater than 100. At least one sub-unit required
+func NewPercentageChecker(name string, percent float64, units ...probeService) (*compositeChecker, error) {
+	if len(units) == 0 {
+		return nil, ErrCompositeUnitsNotSet
+	}
+
+	if percent <= 0 || percent > percentMultiplier {
+		return nil, fmt.Errorf("%w: %g", ErrCompositePercentOutOfRange, percent)
+	}
+
+	return newCompositeChecker(name, func(total int) int {
+		return int(math.Ceil(float64(total) * percent / percentMultiplier))
+	}, units), nil
+}
@@ -0,0 +1,285 @@
+/*
+ *
+ *
+ * MIT NON-AI License
+ *
+ * Copyright (c) 2022-2024 Aleksei Kotelnikov(gudron2s@gmail.com)
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy of the software and associated documentation files (the "Software"),
+ * to deal in the Software without restriction, including without limitation the rights to use, copy, modify, merge, publish, distribute, sublicense,
+ * and/or sell copies of the Software, and to permit persons to whom the Software is furnished to do so, subject to the following conditions.
+ *
+ * The above copyright notice and this permission notice shall be included in all copies or substantial portions of the Software.
+ *
+ * In addition, the following restrictions apply:
+ *
+ * 1. The Software and any modifications made to it may not be used for the purpose of training or improving machine learning algorithms,
+ * including but not limited to artificial intelligence, natural language processing, or data mining. This condition applies to any derivatives,
+ * modifications, or updates based on the Software code. Any usage of the Software in an AI-training dataset is considered a breach of this License.
+ *
+ * 2. The Software may not be included in any dataset used for training or improving machine learning algorithms,
+ * including but not limited to artificial intelligence, natural language processing, or data mining.
+ *
+ * 3. Any person or organization found to be in violation of these restrictions will be subject to legal action and may be held liable
+ * for any damages resulting from such use.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM,
+ * DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE
+ * OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+ *
+ */
+package healthcheck
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func newTestSubUnits(statuses ...CheckStatus) []probeService {
+	names := []string{"node_a", "node_b", "node_c", "node_d"}
+	units := make([]probeService, 0, len(statuses))
+
+	for i, status := range statuses {
+		units = append(units, newFakeProbeUnit(names[i], status))
+	}
+
+	return units
+}
+
+func TestCompositeChecker_Check(t *testing.T) {
+	const (
+		pass = CheckStatusPass
+		warn = CheckStatusWarn
+		fail = CheckStatusFail
+	)
+
+	all := func(units []probeService) (*compositeChecker, error) {
+		return NewAllChecker("nodes", units...)
+	}
+	anyOf := func(units []probeService) (*compositeChecker, error) {
+		return NewAnyChecker("nodes", units...)
+	}
+	atLeast := func(count uint) func(units []probeService) (*compositeChecker, error) {
+		return func(units []probeService) (*compositeChecker, error) {
+			return NewAtLeastChecker("nodes", count, units...)
+		}
+	}
+	percentage := func(percent float64) func(units []probeService) (*compositeChecker, error) {
+		return func(units []probeService) (*compositeChecker, error) {
+			return NewPercentageChecker("nodes", percent, units...)
+		}
+	}
+
+	testCases := []struct {
+		name           string
+		constructor    func(units []probeService) (*compositeChecker, error)
+		statuses       []CheckStatus
+		expectedStatus CheckStatus
+		expectedError  string
+	}{
+		{
+			name:           "all healthy",
+			constructor:    all,
+			statuses:       []CheckStatus{pass, pass, pass},
+			expectedStatus: pass,
+			expectedError:  "",
+		},
+		{
+			name:           "all with one failed",
+			constructor:    all,
+			statuses:       []CheckStatus{pass, fail, pass},
+			expectedStatus: fail,
+			expectedError:  "healthy 2 of 3 checks, required 3: node_b: node_b fail",
+		},
+		{
+			name:           "all with one warned",
+			constructor:    all,
+			statuses:       []CheckStatus{pass, warn, pass},
+			expectedStatus: warn,
+			expectedError:  "degraded 1 of 3 checks: node_b: node_b warn",
+		},
+		{
+			name:           "any with one healthy",
+			constructor:    anyOf,
+			statuses:       []CheckStatus{fail, fail, pass},
+			expectedStatus: pass,
+			expectedError:  "",
+		},
+		{
+			name:           "any with all failed",
+			constructor:    anyOf,
+			statuses:       []CheckStatus{fail, fail},
+			expectedStatus: fail,
+			expectedError:  "healthy 0 of 2 checks, required 1: node_a: node_a fail; node_b: node_b fail",
+		},
+		{
+			name:           "any with warned only",
+			constructor:    anyOf,
+			statuses:       []CheckStatus{fail, warn},
+			expectedStatus: warn,
+			expectedError:  "degraded 1 of 2 checks: node_b: node_b warn",
+		},
+		{
+			name:           "at least quorum reached",
+			constructor:    atLeast(2),
+			statuses:       []CheckStatus{pass, fail, pass},
+			expectedStatus: pass,
+			expectedError:  "",
+		},
+		{
+			name:           "at least quorum not reached",
+			constructor:    atLeast(2),
+			statuses:       []CheckStatus{fail, fail, pass},
+			expectedStatus: fail,
+			expectedError:  "healthy 1 of 3 checks, required 2: node_a: node_a fail; node_b: node_b fail",
+		},
+		{
+			name:           "at least quorum reached with warned unit",
+			constructor:    atLeast(2),
+			statuses:       []CheckStatus{warn, fail, pass},
+			expectedStatus: warn,
+			expectedError:  "degraded 1 of 3 checks: node_a: node_a warn",
+		},
+		{
+			name:           "percentage rounded up",
+			constructor:    percentage(50),
+			statuses:       []CheckStatus{pass, fail, fail},
+			expectedStatus: fail,
+			expectedError:  "healthy 1 of 3 checks, required 2: node_b: node_b fail; node_c: node_c fail",
+		},
+		{
+			name:           "percentage reached",
+			constructor:    percentage(50),
+			statuses:       []CheckStatus{pass, fail, fail, pass},
+			expectedStatus: pass,
+			expectedError:  "",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			checker, err := testCase.constructor(newTestSubUnits(testCase.statuses...))
+			if err != nil {
+				t.Fatalf("unable to create composite checker: %s", err)
+			}
+
+			result := checker.Check(context.Background())
+			if result.Status != testCase.expectedStatus {
+				t.Fatalf("expected status %s, got %s: %s", testCase.expectedStatus, result.Status, result.Error)
+			}
+
+			if result.Error != testCase.expectedError {
+				t.Fatalf("expected error %q, got %q", testCase.expectedError, result.Error)
+			}
+
+			if len(result.Checks) != len(testCase.statuses) {
+				t.Fatalf("expected %d sub-results, got %d", len(testCase.statuses), len(result.Checks))
+			}
+
+			if checker.IsHealed(context.Background()) != result.IsHealthy() {
+				t.Fatal("IsHealed result differs from Check result")
+			}
+		})
+	}
+}
+
+func TestCompositeChecker_Nested(t *testing.T) {
+	inner, err := NewAnyChecker("replicas", newTestSubUnits(CheckStatusFail, CheckStatusWarn)...)
+	if err != nil {
+		t.Fatalf("unable to create composite checker: %s", err)
+	}
+
+	outer, err := NewAllChecker("storage", inner, newFakeProbeUnit("primary", CheckStatusPass))
+	if err != nil {
+		t.Fatalf("unable to create composite checker: %s", err)
+	}
+
+	result := outer.Check(context.Background())
+	if result.Status != CheckStatusWarn {
+		t.Fatalf("expected warn status propagated from nested composite, got %s", result.Status)
+	}
+
+	if len(result.Checks) != 2 || len(result.Checks[0].Checks) != 2 {
+		t.Fatalf("expected sub-results of nested composite in report")
+	}
+}
+
+func TestCompositeChecker_Constructors(t *testing.T) {
+	testCases := []struct {
+		name          string
+		constructor   func() (*compositeChecker, error)
+		expectedError error
+	}{
+		{
+			name: "all without units",
+			constructor: func() (*compositeChecker, error) {
+				return NewAllChecker("nodes")
+			},
+			expectedError: ErrCompositeUnitsNotSet,
+		},
+		{
+			name: "any without units",
+			constructor: func() (*compositeChecker, error) {
+				return NewAnyChecker("nodes")
+			},
+			expectedError: ErrCompositeUnitsNotSet,
+		},
+		{
+			name: "percentage without units",
+			constructor: func() (*compositeChecker, error) {
+				return NewPercentageChecker("nodes", 50)
+			},
+			expectedError: ErrCompositeUnitsNotSet,
+		},
+		{
+			name: "at least without units",
+			constructor: func() (*compositeChecker, error) {
+				return NewAtLeastChecker("nodes", 1)
+			},
+			expectedError: ErrCompositeCountOutOfRange,
+		},
+		{
+			name: "at least zero count",
+			constructor: func() (*compositeChecker, error) {
+				return NewAtLeastChecker("nodes", 0, newTestSubUnits(CheckStatusPass)...)
+			},
+			expectedError: ErrCompositeCountOutOfRange,
+		},
+		{
+			name: "at least count more than units",
+			constructor: func() (*compositeChecker, error) {
+				return NewAtLeastChecker("nodes", 3, newTestSubUnits(CheckStatusPass, CheckStatusPass)...)
+			},
+			expectedError: ErrCompositeCountOutOfRange,
+		},
+		{
+			name: "percentage out of range",
+			constructor: func() (*compositeChecker, error) {
+				return NewPercentageChecker("nodes", 101, newTestSubUnits(CheckStatusPass)...)
+			},
+			expectedError: ErrCompositePercentOutOfRange,
+		},
+		{
+			name: "all with units",
+			constructor: func() (*compositeChecker, error) {
+				return NewAllChecker("nodes", newTestSubUnits(CheckStatusPass)...)
+			},
+			expectedError: nil,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			checker, err := testCase.constructor()
+			if !errors.Is(err, testCase.expectedError) {
+				t.Fatalf("expected error %v, got %v", testCase.expectedError, err)
+			}
+
+			if (checker == nil) == (testCase.expectedError == nil) {
+				t.Fatalf("unexpected checker %v with error %v", checker, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,240 @@
+/*
+ *
+ *
+ * MIT NON-AI License
+ *
+ * Copyright (c) 2022-2024 Aleksei Kotelnikov(gudron2s@gmail.com)
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy of the software and associated documentation files (the "Software"),
+ * to deal in the Software without restriction, including without limitation the rights to use, copy, modify, merge, publish, distribute, sublicense,
+ * and/or sell copies of the Software, and to permit persons to whom the Software is furnished to do so, subject to the following conditions.
+ *
+ * The above copyright notice and this permission notice shall be included in all copies or substantial portions of the Software.
+ *
+ * In addition, the following restrictions apply:
+ *
+ * 1. The Software and any modifications made to it may not be used for the purpose of training or improving machine learning algorithms,
+ * including but not limited to artificial intelligence, natural language processing, or data mining. This condition applies to any derivatives,
+ * modifications, or updates based on the Software code. Any usage of the Software in an AI-training dataset is considered a breach of this License.
+ *
+ * 2. The Software may not be included in any dataset used for training or improving machine learning algorithms,
+ * including but not limited to artificial intelligence, natural language processing, or data mining.
+ *
+ * 3. Any person or organization found to be in violation of these restrictions will be subject to legal action and may be held liable
+ * for any damages resulting from such use.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM,
+ * DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE
+ * OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+ *
+ */
+
+package healthcheck
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const ObservedRecordsKey = "records"
+
+type DNSRecordType string
+
+const (
+	DNSRecordTypeA    DNSRecordType = "A"
+	DNSRecordTypeAAAA DNSRecordType = "AAAA"
+	DNSRecordTypeSRV  DNSRecordType = "SRV"
+)
+
+const dnsCheckerDefaultTimeout = 5 * time.Second
+
+var ErrUnknownDNSRecordType = errors.New("unknown dns record type")
+
+// DNSQuery - resolved name and conditions of resolution result
+type DNSQuery struct {
+	// Name - resolved name, e.g. postgres.default.svc.cluster.local or _grpc._tcp.signer.default.svc.cluster.local
+	Name string
+	// Type - type of records - A, AAAA or SRV
+	Type DNSRecordType
+	// MinRecords - min count of resolved records, default - 1
+	MinRecords int
+	// ExpectedValues - values which must be resolved - IP addresses, or target:port for SRV records
+	ExpectedValues []string
+}
+
+// DNSCheckerOptions - options of dns resolution checker
+type DNSCheckerOptions struct {
+	// ResolverAddress - address of dns server, host:port. System resolver used if empty
+	ResolverAddress string
+	// Timeout - timeout of each query, default - 5s
+	Timeout time.Duration
+	// Queries - resolved names, at least one query required
+	Queries []DNSQuery
+}
+
+// dnsChecker - probe unit of dns resolution. Queries resolved concurrently,
+// result of each query included as sub-result
+type dnsChecker struct {
+	CheckerBase
+
+	resolver *net.Resolver
+	options  DNSCheckerOptions
+}
+
+func (c *dnsChecker) Check(ctx context.Context) *CheckResult {
+	result := NewCheckResult(c.name)
+	result.Checks = make([]*CheckResult, len(c.options.Queries))
+
+	wg := sync.WaitGroup{}
+	for i, query := range c.options.Queries {
+		wg.Add(1)
+
+		go func(index int, query DNSQuery) {
+			defer wg.Done()
+
+			result.Checks[index] = c.checkQuery(ctx, query)
+		}(i, query)
+	}
+
+	wg.Wait()
+
+	result.setStatusBySubResults()
+
+	return result
+}
+
+func (c *dnsChecker) checkQuery(ctx context.Context, query DNSQuery) *CheckResult {
+	result := NewCheckResult(string(query.Type) + " " + query.Name)
+	result.Timestamp = time.Now()
+
+	timeout := c.options.Timeout
+	if timeout <= 0 {
+		timeout = dnsCheckerDefaultTimeout
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	records, err := c.lookup(ctx, query)
+
+	result.Duration = time.Since(result.Timestamp)
+
+	if err != nil {
+		result.Status = CheckStatusFail
+		result.Error = err.Error()
+
+		return result
+	}
+
+	result.Observed[ObservedLatencyKey] = result.Duration.String()
+	result.Observed[ObservedRecordsKey] = records
+
+	minRecords := max(query.MinRecords, 1)
+	if len(records) < minRecords {
+		result.Status = CheckStatusFail
+		result.Error = fmt.Sprintf("resolved %d records, min %d records required", len(records), minRecords)
+
+		return result
+	}
+
+	for _, expectedValue := range query.ExpectedValues {
+		if !slices.Contains(records, expectedValue) {
+			result.Status = CheckStatusFail
+			result.Error = fmt.Sprintf("expected value %s not resolved", expectedValue)
+
+			return result
+		}
+	}
+
+	return result
+}
+
+func (c *dnsChecker) lookup(ctx context.Context, query DNSQuery) ([]string, error) {
+	switch query.Type {
+	case DNSRecordTypeA, DNSRecordTypeAAAA:
+		network := "ip4"
+		if query.Type == DNSRecordTypeAAAA {
+			network = "ip6"
+		}
+
+		ips, err := c.resolver.LookupIP(ctx, network, query.Name)
+		if err != nil {
+			return nil, fmt.Errorf("lookup failed: %w", err)
+		}
+
+		records := make([]string, 0, len(ips))
+		for _, ip := range ips {
+			records = append(records, ip.String())
+		}
+
+		return records, nil
+	case DNSRecordTypeSRV:
+		_, srvRecords, err := c.resolver.LookupSRV(ctx, "", "", query.Name)
+		if err != nil {
+			return nil, fmt.Errorf("lookup failed: %w", err)
+		}
+
+		records := make([]string, 0, len(srvRecords))
+		for _, srvRecord := range srvRecords {
+			records = append(records,
+				net.JoinHostPort(strings.TrimSuffix(srvRecord.Target, "."), strconv.Itoa(int(srvRecord.Port))))
+		}
+
+		return records, nil
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnknownDNSRecordType, query.Type)
+	}
+}
+
+// NewDNSChecker - probe unit of dns resolution by configurable resolver.
+// Checks min count of records and expected values, reports lookup latency.
+// Returns error if queries not set in options or type of query records unknown
+func NewDNSChecker(name string, options *DNSCheckerOptions) (*dnsChecker, error) {
+	if options == nil || len(options.Queries) == 0 {
+		return nil, fmt.Errorf("%w: queries of dns checker %s", ErrCheckerOptionRequired, name)
+	}
+
+	for _, query := range options.Queries {
+		switch query.Type {
+		case DNSRecordTypeA, DNSRecordTypeAAAA, DNSRecordTypeSRV:
+		default:
+			return nil, fmt.Errorf("%w: %s of query %s", ErrUnknownDNSRecordType, query.Type, query.Name)
+		}
+	}
+
+	resolver := net.DefaultResolver
+
+	if options.ResolverAddress != "" {
+		resolverAddress := options.ResolverAddress
+
+		//nolint:exhaustruct // it's ok here. pure go resolver with custom dns server address
+		resolver = &net.Resolver{
+			PreferGo: true,
+			Dial: func(ctx context.Context, network, _ string) (net.Conn, error) {
+				//nolint:exhaustruct // default dialer, timeout by context
+				dialer := &net.Dialer{}
+
+				return dialer.DialContext(ctx, network, resolverAddress)
+			},
+		}
+	}
+
+	checker := &dnsChecker{
+		CheckerBase: CheckerBase{name: name, checkFunc: nil},
+
+		resolver: resolver,
+		options:  *options,
+	}
+
+	checker.checkFunc = checker.Check
+
+	return checker, nil
+}
@@ -0,0 +1,276 @@
+/*
+ *
+ *
+ * MIT NON-AI License
+ *
+ * Copyright (c) 2022-2024 Aleksei Kotelnikov(gudron2s@gmail.com)
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy of the software and associated documentation files (the "Software"),
+ * to deal in the Software without restriction, including without limitation the rights to use, copy, modify, merge, publish, distribute, sublicense,
+ * and/or sell copies of the Software, and to permit persons to whom the Software is furnished to do so, subject to the following conditions.
+ *
+ * The above copyright notice and this permission notice shall be included in all copies or substantial portions of the Software.
+ *
+ * In addition, the following restrictions apply:
+ *
+ * 1. The Software and any modifications made to it may not be used for the purpose of training or improving machine learning algorithms,
+ * including but not limited to artificial intelligence, natural language processing, or data mining. This condition applies to any derivatives,
+ * modifications, or updates based on the Software code. Any usage of the Software in an AI-training dataset is considered a breach of this License.
+ *
+ * 2. The Software may not be included in any dataset used for training or improving machine learning algorithms,
+ * including but not limited to artificial intelligence, natural language processing, or data mining.
+ *
+ * 3. Any person or organization found to be in violation of these restrictions will be subject to legal action and may be held liable
+ * for any damages resulting from such use.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM,
+ * DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE
+ * OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+ *
+ */
+package healthcheck
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"net"
+	"strings"
+	"testing"
+	"time"
+)
+
+const (
+	dnsTypeA    = 1
+	dnsTypeAAAA = 28
+	dnsTypeSRV  = 33
+
+	dnsHeaderSize = 12
+)
+
+// fakeDNSRecord - answer of dns server stand-in, ip for A and AAAA records, target and port for SRV records
+type fakeDNSRecord struct {
+	ip     net.IP
+	target string
+	port   uint16
+}
+
+// startFakeDNSServer - udp dns server stand-in, answers by records of lowercase name and query type.
+// Unknown names answered with NXDOMAIN
+func startFakeDNSServer(t *testing.T, records map[string]map[uint16][]fakeDNSRecord) string {
+	t.Helper()
+
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("unable to listen: %s", err)
+	}
+
+	t.Cleanup(func() {
+		_ = conn.Close()
+	})
+
+	go func() {
+		buf := make([]byte, 1500)
+
+		for {
+			n, addr, readErr := conn.ReadFrom(buf)
+			if readErr != nil {
+				return
+			}
+
+			resp := buildFakeDNSResponse(buf[:n], records)
+			if resp != nil {
+				_, _ = conn.WriteTo(resp, addr)
+			}
+		}
+	}()
+
+	return conn.LocalAddr().String()
+}
+
+func buildFakeDNSResponse(req []byte, records map[string]map[uint16][]fakeDNSRecord) []byte {
+	if len(req) < dnsHeaderSize {
+		return nil
+	}
+
+	labels := make([]string, 0)
+	offset := dnsHeaderSize
+
+	for offset < len(req) && req[offset] != 0 {
+		length := int(req[offset])
+		if offset+1+length > len(req) {
+			return nil
+		}
+
+		labels = append(labels, string(req[offset+1:offset+1+length]))
+		offset += 1 + length
+	}
+
+	// zero length of root label, type and class of question
+	questionEnd := offset + 5
+	if questionEnd > len(req) {
+		return nil
+	}
+
+	name := strings.ToLower(strings.Join(labels, "."))
+	queryType := binary.BigEndian.Uint16(req[offset+1 : offset+3])
+
+	nameRecords, isExists := records[name]
+
+	resp := make([]byte, 0, 512)
+	resp = append(resp, req[0:2]...)
+
+	// response, recursion desired and available, NXDOMAIN code for unknown names
+	flags := uint16(0x8180)
+	if !isExists {
+		flags |= 3
+	}
+
+	answers := nameRecords[queryType]
+
+	resp = binary.BigEndian.AppendUint16(resp, flags)
+	resp = binary.BigEndian.AppendUint16(resp, 1)
+	resp = binary.BigEndian.AppendUint16(resp, uint16(len(answers)))
+	resp = binary.BigEndian.AppendUint16(resp, 0)
+	resp = binary.BigEndian.AppendUint16(resp, 0)
+	resp = append(resp, req[dnsHeaderSize:questionEnd]...)
+
+	for _, answer := range answers {
+		rdata := answer.rdata(queryType)
+
+		// pointer to name of question
+		resp = append(resp, 0xC0, dnsHeaderSize)
+		resp = binary.BigEndian.AppendUint16(resp, queryType)
+		resp = binary.BigEndian.AppendUint16(resp, 1)
+		resp = binary.BigEndian.AppendUint32(resp, 60)
+		resp = binary.BigEndian.AppendUint16(resp, uint16(len(rdata)))
+		resp = append(resp, rdata...)
+	}
+
+	return resp
+}
+
+func (r fakeDNSRecord) rdata(queryType uint16) []byte {
+	switch queryType {
+	case dnsTypeA:
+		return r.ip.To4()
+	case dnsTypeAAAA:
+		return r.ip.To16()
+	default:
+		rdata := make([]byte, 0, 64)
+		rdata = binary.BigEndian.AppendUint16(rdata, 0)
+		rdata = binary.BigEndian.AppendUint16(rdata, 0)
+		rdata = binary.BigEndian.AppendUint16(rdata, r.port)
+
+		for _, label := range strings.Split(strings.TrimSuffix(r.target, "."), ".") {
+			rdata = append(rdata, byte(len(label)))
+			rdata = append(rdata, label...)
+		}
+
+		return append(rdata, 0)
+	}
+}
+
+func TestDNSChecker(t *testing.T) {
+	resolverAddress := startFakeDNSServer(t, map[string]map[uint16][]fakeDNSRecord{
+		"postgres.wallet.test": {
+			dnsTypeA: {
+				{ip: net.ParseIP("10.0.0.1"), target: "", port: 0},
+				{ip: net.ParseIP("10.0.0.2"), target: "", port: 0},
+			},
+			dnsTypeAAAA: {{ip: net.ParseIP("fd00::1"), target: "", port: 0}},
+		},
+		"_grpc._tcp.signer.wallet.test": {
+			dnsTypeSRV: {{ip: nil, target: "signer-0.wallet.test.", port: 9090}},
+		},
+	})
+
+	testCases := []struct {
+		name           string
+		query          DNSQuery
+		expectedStatus CheckStatus
+	}{
+		{
+			name:           "A records",
+			query:          DNSQuery{Name: "postgres.wallet.test.", Type: DNSRecordTypeA, MinRecords: 2, ExpectedValues: nil},
+			expectedStatus: CheckStatusPass,
+		},
+		{
+			name: "AAAA record with expected value",
+			query: DNSQuery{
+				Name: "postgres.wallet.test.", Type: DNSRecordTypeAAAA, MinRecords: 0, ExpectedValues: []string{"fd00::1"},
+			},
+			expectedStatus: CheckStatusPass,
+		},
+		{
+			name: "SRV record with expected value",
+			query: DNSQuery{
+				Name: "_grpc._tcp.signer.wallet.test.", Type: DNSRecordTypeSRV,
+				MinRecords: 1, ExpectedValues: []string{"signer-0.wallet.test:9090"},
+			},
+			expectedStatus: CheckStatusPass,
+		},
+		{
+			name:           "not enough records",
+			query:          DNSQuery{Name: "postgres.wallet.test.", Type: DNSRecordTypeA, MinRecords: 3, ExpectedValues: nil},
+			expectedStatus: CheckStatusFail,
+		},
+		{
+			name: "expected value not resolved",
+			query: DNSQuery{
+				Name: "postgres.wallet.test.", Type: DNSRecordTypeA, MinRecords: 1, ExpectedValues: []string{"10.0.0.3"},
+			},
+			expectedStatus: CheckStatusFail,
+		},
+		{
+			name:           "unknown name",
+			query:          DNSQuery{Name: "redis.wallet.test.", Type: DNSRecordTypeA, MinRecords: 0, ExpectedValues: nil},
+			expectedStatus: CheckStatusFail,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			checker, err := NewDNSChecker("dns", &DNSCheckerOptions{
+				ResolverAddress: resolverAddress,
+				Timeout:         time.Second,
+				Queries:         []DNSQuery{testCase.query},
+			})
+			if err != nil {
+				t.Fatalf("unable to create checker: %s", err)
+			}
+
+			result := checker.Check(context.Background())
+			if result.Status != testCase.expectedStatus {
+				t.Fatalf("unexpected status: %s, expected: %s, error: %s",
+					result.Status, testCase.expectedStatus, result.Error)
+			}
+
+			if len(result.Checks) != 1 || result.Checks[0].Status != testCase.expectedStatus {
+				t.Fatalf("unexpected sub-results: %v", result.Checks)
+			}
+		})
+	}
+}
+
+func TestDNSCheckerOptionsValidation(t *testing.T) {
+	_, err := NewDNSChecker("dns", nil)
+	if !errors.Is(err, ErrCheckerOptionRequired) {
+		t.Fatalf("unexpected error on nil options: %v", err)
+	}
+
+	_, err = NewDNSChecker("dns", &DNSCheckerOptions{ResolverAddress: "", Timeout: 0, Queries: nil})
+	if !errors.Is(err, ErrCheckerOptionRequired) {
+		t.Fatalf("unexpected error without queries: %v", err)
+	}
+
+	_, err = NewDNSChecker("dns", &DNSCheckerOptions{
+		ResolverAddress: "",
+		Timeout:         0,
+		Queries:         []DNSQuery{{Name: "wallet.test.", Type: "MX", MinRecords: 0, ExpectedValues: nil}},
+	})
+	if !errors.Is(err, ErrUnknownDNSRecordType) {
+		t.Fatalf("unexpected error on unknown record type: %v", err)
+	}
+}
@@ -0,0 +1,173 @@
+/*
+ *
+ *
+ * MIT NON-AI License
+ *
+ * Copyright (c) 2022-2024 Aleksei Kotelnikov(gudron2s@gmail.com)
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy of the software and associated documentation files (the "Software"),
+ * to deal in the Software without restriction, including without limitation the rights to use, copy, modify, merge, publish, distribute, sublicense,
+ * and/or sell copies of the Software, and to permit persons to whom the Software is furnished to do so, subject to the following conditions.
+ *
+ * The above copyright notice and this permission notice shall be included in all copies or substantial portions of the Software.
+ *
+ * In addition, the following restrictions apply:
+ *
+ * 1. The Software and any modifications made to it may not be used for the purpose of training or improving machine learning algorithms,
+ * including but not limited to artificial intelligence, natural language processing, or data mining. This condition applies to any derivatives,
+ * modifications, or updates based on the Software code. Any usage of the Software in an AI-training dataset is considered a breach of this License.
+ *
+ * 2. The Software may not be included in any dataset used for training or improving machine learning algorithms,
+ * including but not limited to artificial intelligence, natural language processing, or data mining.
+ *
+ * 3. Any person or organization found to be in violation of these restrictions will be subject to legal action and may be held liable
+ * for any damages resulting from such use.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM,
+ * DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE
+ * OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+ *
+ */
+
+package healthcheck
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ethereumNodeChecker - probe unit of ethereum-like node sync status. Uses eth_syncing, eth_blockNumber
+// and net_peerCount json-rpc methods
+type ethereumNodeChecker struct {
+	CheckerBase
+
+	client  *nodeAPIClient
+	options BlockchainNodeCheckerOptions
+}
+
+func (c *ethereumNodeChecker) Check(ctx context.Context) *CheckResult {
+	result := NewCheckResult(c.name)
+
+	state, err := c.getState(ctx)
+	if err != nil {
+		result.Status = CheckStatusFail
+		result.Error = err.Error()
+
+		return result
+	}
+
+	evaluateBlockchainNodeState(state, &c.options, result)
+
+	return result
+}
+
+func (c *ethereumNodeChecker) getState(ctx context.Context) (*blockchainNodeState, error) {
+	ctx, cancel := withNodeCheckTimeout(ctx, c.options.Timeout)
+	defer cancel()
+
+	//nolint:exhaustruct // it's ok here. fields filled by node responses
+	state := &blockchainNodeState{}
+
+	var syncing json.RawMessage
+
+	err := c.client.Call(ctx, "eth_syncing", nil, &syncing)
+	if err != nil {
+		return nil, err
+	}
+
+	// eth_syncing returns false if node is synced, otherwise object with sync progress
+	if !bytes.Equal(bytes.TrimSpace(syncing), []byte("false")) {
+		var progress struct {
+			CurrentBlock string `json:"currentBlock"`
+			HighestBlock string `json:"highestBlock"`
+		}
+
+		err = json.Unmarshal(syncing, &progress)
+		if err != nil {
+			return nil, fmt.Errorf("%w: eth_syncing: %s", ErrNodeUnexpectedResponse, err)
+		}
+
+		currentBlock, err := parseHexUint64(progress.CurrentBlock)
+		if err != nil {
+			return nil, fmt.Errorf("eth_syncing: current block: %w", err)
+		}
+
+		highestBlock, err := parseHexUint64(progress.HighestBlock)
+		if err != nil {
+			return nil, fmt.Errorf("eth_syncing: highest block: %w", err)
+		}
+
+		state.IsSyncing = true
+		if highestBlock > currentBlock {
+			state.LagBlocks = highestBlock - currentBlock
+		}
+	}
+
+	var blockNumber string
+
+	err = c.client.Call(ctx, "eth_blockNumber", nil, &blockNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	state.Height, err = parseHexUint64(blockNumber)
+	if err != nil {
+		return nil, fmt.Errorf("eth_blockNumber: %w", err)
+	}
+
+	var peerCount string
+
+	err = c.client.Call(ctx, "net_peerCount", nil, &peerCount)
+	if err != nil {
+		return nil, err
+	}
+
+	peers, err := parseHexUint64(peerCount)
+	if err != nil {
+		return nil, fmt.Errorf("net_peerCount: %w", err)
+	}
+
+	state.Peers = &peers
+
+	return state, nil
+}
+
+// parseHexUint64 - parse hex quantity of ethereum json-rpc api, e.g. 0x1b4
+func parseHexUint64(value string) (uint64, error) {
+	digits, isPrefixed := strings.CutPrefix(value, "0x")
+	if !isPrefixed || digits == "" {
+		return 0, fmt.Errorf("%w: invalid hex quantity %q", ErrNodeUnexpectedResponse, value)
+	}
+
+	number, err := strconv.ParseUint(digits, 16, 64)
+	if err != nil {
+		return 0, fmt.Errorf("%w: invalid hex quantity %q", ErrNodeUnexpectedResponse, value)
+	}
+
+	return number, nil
+}
+
+// NewEthereumNodeChecker - returns probe unit of ethereum-like node, e.g. geth, erigon, nethermind, bsc, polygon.
+// Returns error if url not set in options
+func NewEthereumNodeChecker(name string, options *BlockchainNodeCheckerOptions) (*ethereumNodeChecker, error) {
+	err := validateBlockchainNodeCheckerOptions(name, options)
+	if err != nil {
+		return nil, err
+	}
+
+	checker := &ethereumNodeChecker{
+		CheckerBase: CheckerBase{name: name, checkFunc: nil},
+
+		client:  newNodeAPIClient(options),
+		options: *options,
+	}
+
+	checker.checkFunc = checker.Check
+
+	return checker, nil
+}
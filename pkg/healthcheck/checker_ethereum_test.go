@@ -0,0 +1,106 @@
+/*
+ *
+ *
+ * MIT NON-AI License
+ *
+ * Copyright (c) 2022-2024 Aleksei Kotelnikov(gudron2s@gmail.com)
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy of the software and associated documentation files (the "Software"),
+ * to deal in the Software without restriction, including without limitation the rights to use, copy, modify, merge, publish, distribute, sublicense,
+ * and/or sell copies of the Software, and to permit persons to whom the Software is furnished to do so, subject to the following conditions.
+ *
+ * The above copyright notice and this permission notice shall be included in all copies or substantial portions of the Software.
+ *
+ * In addition, the following restrictions apply:
+ *
+ * 1. The Software and any modifications made to it may not be used for the purpose of training or improving machine learning algorithms,
+ * including but not limited to artificial intelligence, natural language processing, or data mining. This condition applies to any derivatives,
+ * modifications, or updates based on the Software code. Any usage of the Software in an AI-training dataset is considered a breach of this License.
+ *
+ * 2. The Software may not be included in any dataset used for training or improving machine learning algorithms,
+ * including but not limited to artificial intelligence, natural language processing, or data mining.
+ *
+ * 3. Any person or organization found to be in violation of these restrictions will be subject to legal action and may be held liable
+ * for any damages resulting from such use.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM,
+ * DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE
+ * OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+ *
+ */
+package healthcheck
+
+import (
+	"context"
+	"testing"
+)
+
+func TestEthereumNodeChecker(t *testing.T) {
+	syncProgress := map[string]any{"currentBlock": "0x64", "highestBlock": "0xc8"}
+
+	testCases := []struct {
+		name           string
+		results        map[string]any
+		options        BlockchainNodeCheckerOptions
+		expectedStatus CheckStatus
+	}{
+		{
+			name:           "synced node",
+			results:        map[string]any{"eth_syncing": false, "eth_blockNumber": "0x1b4", "net_peerCount": "0x19"},
+			options:        BlockchainNodeCheckerOptions{MinPeers: 5}, //nolint:exhaustruct // only tested options
+			expectedStatus: CheckStatusPass,
+		},
+		{
+			name:           "syncing node without lag thresholds",
+			results:        map[string]any{"eth_syncing": syncProgress, "eth_blockNumber": "0x64", "net_peerCount": "0x19"},
+			options:        BlockchainNodeCheckerOptions{}, //nolint:exhaustruct // defaults
+			expectedStatus: CheckStatusFail,
+		},
+		{
+			name:    "syncing node within warn lag",
+			results: map[string]any{"eth_syncing": syncProgress, "eth_blockNumber": "0x64", "net_peerCount": "0x19"},
+			//nolint:exhaustruct // only tested options
+			options:        BlockchainNodeCheckerOptions{WarnLagBlocks: 50, MaxLagBlocks: 500},
+			expectedStatus: CheckStatusWarn,
+		},
+		{
+			name:    "syncing node exceeds max lag",
+			results: map[string]any{"eth_syncing": syncProgress, "eth_blockNumber": "0x64", "net_peerCount": "0x19"},
+			//nolint:exhaustruct // only tested options
+			options:        BlockchainNodeCheckerOptions{WarnLagBlocks: 10, MaxLagBlocks: 50},
+			expectedStatus: CheckStatusFail,
+		},
+		{
+			name:    "few peers",
+			results: map[string]any{"eth_syncing": false, "eth_blockNumber": "0x1b4", "net_peerCount": "0x3"},
+			//nolint:exhaustruct // only tested options
+			options:        BlockchainNodeCheckerOptions{MinPeers: 1, WarnPeers: 5},
+			expectedStatus: CheckStatusWarn,
+		},
+		{
+			name:           "json-rpc error response",
+			results:        map[string]any{"eth_syncing": false},
+			options:        BlockchainNodeCheckerOptions{}, //nolint:exhaustruct // defaults
+			expectedStatus: CheckStatusFail,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			options := testCase.options
+			options.URL = startFakeJSONRPCServer(t, testCase.results).URL
+
+			checker, err := NewEthereumNodeChecker("ethereum", &options)
+			if err != nil {
+				t.Fatalf("unable to create checker: %s", err)
+			}
+
+			result := checker.Check(context.Background())
+			if result.Status != testCase.expectedStatus {
+				t.Fatalf("unexpected status: %s, expected: %s, error: %s",
+					result.Status, testCase.expectedStatus, result.Error)
+			}
+		})
+	}
+}
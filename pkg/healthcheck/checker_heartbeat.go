@@ -0,0 +1,172 @@
+/*
+ *
+ *
+ * MIT NON-AI License
+ *
+ * Copyright (c) 2022-2024 Aleksei Kotelnikov(gudron2s@gmail.com)
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy of the software and associated documentation files (the "Software"),
+ * to deal in the Software without restriction, including without limitation the rights to use, copy, modify, merge, publish, distribute, sublicense,
+ * and/or sell copies of the Software, and to permit persons to whom the Software is furnished to do so, subject to the following conditions.
+ *
+ * The above copyright notice and this permission notice shall be included in all copies or substantial portions of the Software.
+ *
+ * In addition, the following restrictions apply:
+ *
+ * 1. The Software and any modifications made to it may not be used for the purpose of training or improving machine learning algorithms,
+ * including but not limited to artificial intelligence, natural language processing, or data mining. This condition applies to any derivatives,
+ * modifications, or updates based on the Software code. Any usage of the Software in an AI-training dataset is considered a breach of this License.
+ *
+ * 2. The Software may not be included in any dataset used for training or improving machine learning algorithms,
+ * including but not limited to artificial intelligence, natural language processing, or data mining.
+ *
+ * 3. Any person or organization found to be in violation of these restrictions will be subject to legal action and may be held liable
+ * for any damages resulting from such use.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM,
+ * DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE
+ * OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+ *
+ */
+
+package healthcheck
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// heartbeatHandle - heartbeat handle of long-running worker. Worker must call Beat function more often than deadline
+type heartbeatHandle struct {
+	watchdog *heartbeatWatchdog
+
+	name         string
+	deadline     time.Duration
+	warnDeadline time.Duration
+	// lastBeatAt - unix nano time of last heartbeat
+	lastBeatAt atomic.Int64
+}
+
+// Beat - report that worker is alive. Safe for concurrent use, cheap enough for call on every loop iteration
+func (h *heartbeatHandle) Beat() {
+	h.lastBeatAt.Store(time.Now().UnixNano())
+}
+
+// Unregister - remove worker from watchdog, e.g. on graceful stop of worker
+func (h *heartbeatHandle) Unregister() {
+	h.watchdog.unregister(h)
+}
+
+// heartbeatWatchdog - probe unit which fails if one of registered workers missed heartbeat deadline
+type heartbeatWatchdog struct {
+	CheckerBase
+
+	handles map[*heartbeatHandle]struct{}
+
+	mu sync.RWMutex
+}
+
+// Register - register long-running worker, returns heartbeat handle of worker.
+// Time of registration is time of first heartbeat
+func (w *heartbeatWatchdog) Register(workerName string, deadline time.Duration) *heartbeatHandle {
+	return w.RegisterWithWarnDeadline(workerName, 0, deadline)
+}
+
+// RegisterWithWarnDeadline - same with Register, but worker check unit warns if worker missed warn deadline.
+// Zero warn deadline disables warning
+func (w *heartbeatWatchdog) RegisterWithWarnDeadline(workerName string,
+	warnDeadline time.Duration,
+	deadline time.Duration,
+) *heartbeatHandle {
+	handle := &heartbeatHandle{
+		watchdog: w,
+
+		name:         workerName,
+		deadline:     deadline,
+		warnDeadline: warnDeadline,
+		lastBeatAt:   atomic.Int64{},
+	}
+	handle.Beat()
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.handles[handle] = struct{}{}
+
+	return handle
+}
+
+func (w *heartbeatWatchdog) unregister(handle *heartbeatHandle) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	delete(w.handles, handle)
+}
+
+// Check - check heartbeat deadlines of all registered workers, result of each worker included as sub-result
+func (w *heartbeatWatchdog) Check(_ context.Context) *CheckResult {
+	now := time.Now()
+
+	w.mu.RLock()
+
+	handles := make([]*heartbeatHandle, 0, len(w.handles))
+	for handle := range w.handles {
+		handles = append(handles, handle)
+	}
+
+	w.mu.RUnlock()
+
+	sort.Slice(handles, func(i, j int) bool {
+		return handles[i].name < handles[j].name
+	})
+
+	result := NewCheckResult(w.name)
+	result.Checks = make([]*CheckResult, 0, len(handles))
+
+	for _, handle := range handles {
+		lastBeatAt := time.Unix(0, handle.lastBeatAt.Load())
+		sinceLastBeat := now.Sub(lastBeatAt)
+
+		workerResult := NewCheckResult(handle.name)
+		workerResult.Timestamp = now
+		workerResult.LastSuccess = lastBeatAt
+
+		switch {
+		case sinceLastBeat > handle.deadline:
+			workerResult.Status = CheckStatusFail
+			workerResult.Error = fmt.Sprintf("no heartbeat for %s, deadline %s",
+				sinceLastBeat.Truncate(time.Millisecond), handle.deadline)
+		case handle.warnDeadline > 0 && sinceLastBeat > handle.warnDeadline:
+			workerResult.Status = CheckStatusWarn
+			workerResult.Error = fmt.Sprintf("no heartbeat for %s, warn deadline %s",
+				sinceLastBeat.Truncate(time.Millisecond), handle.warnDeadline)
+		}
+
+		result.Checks = append(result.Checks, workerResult)
+	}
+
+	result.setStatusBySubResults()
+
+	return result
+}
+
+// NewHeartbeatWatchdog - probe unit for detection of stuck goroutines, e.g. deadlocked consumers.
+// Long-running workers registered by Register function and call Beat function of returned handle
+func NewHeartbeatWatchdog(name string) *heartbeatWatchdog {
+	checker := &heartbeatWatchdog{
+		CheckerBase: CheckerBase{name: name, checkFunc: nil},
+
+		handles: make(map[*heartbeatHandle]struct{}),
+
+		mu: sync.RWMutex{},
+	}
+
+	checker.checkFunc = checker.Check
+
+	return checker
+}
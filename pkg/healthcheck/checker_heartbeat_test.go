@@ -0,0 +1,143 @@
+/*
+ *
+ *
+ * MIT NON-AI License
+ *
+ * Copyright (c) 2022-2024 Aleksei Kotelnikov(gudron2s@gmail.com)
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy of the software and associated documentation files (the "Software"),
+ * to deal in the Software without restriction, including without limitation the rights to use, copy, modify, merge, publish, distribute, sublicense,
+ * and/or sell copies of the Software, and to permit persons to whom the Software is furnished to do so, subject to the following conditions.
+ *
+ * The above copyright notice and this permission notice shall be included in all copies or substantial portions of the Software.
+ *
+ * In addition, the following restrictions apply:
+ *
+ * 1. The Software and any modifications made to it may not be used for the purpose of training or improving machine learning algorithms,
+ * including but not limited to artificial intelligence, natural language processing, or data mining. This condition applies to any derivatives,
+ * modifications, or updates based on the Software code. Any usage of the Software in an AI-training dataset is considered a breach of this License.
+ *
+ * 2. The Software may not be included in any dataset used for training or improving machine learning algorithms,
+ * including but not limited to artificial intelligence, natural language processing, or data mining.
+ *
+ * 3. Any person or organization found to be in violation of these restrictions will be subject to legal action and may be held liable
+ * for any damages resulting from such use.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM,
+ * DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE
+ * OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+ *
+ */
+package healthcheck
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+// setLastBeatAgo - move time of last heartbeat of worker to past, instead of waiting for deadline
+func setLastBeatAgo(handle *heartbeatHandle, ago time.Duration) {
+	handle.lastBeatAt.Store(time.Now().Add(-ago).UnixNano())
+}
+
+func TestHeartbeatWatchdog_Check(t *testing.T) {
+	testCases := []struct {
+		name           string
+		warnDeadline   time.Duration
+		deadline       time.Duration
+		lastBeatAgo    time.Duration
+		expectedStatus CheckStatus
+		expectedError  string
+	}{
+		{
+			name:           "heartbeat within deadline",
+			warnDeadline:   0,
+			deadline:       time.Minute,
+			lastBeatAgo:    time.Second,
+			expectedStatus: CheckStatusPass,
+			expectedError:  "",
+		},
+		{
+			name:           "expired deadline",
+			warnDeadline:   0,
+			deadline:       time.Minute,
+			lastBeatAgo:    2 * time.Minute,
+			expectedStatus: CheckStatusFail,
+			expectedError:  "consumer: no heartbeat for 2m0",
+		},
+		{
+			name:           "expired warn deadline",
+			warnDeadline:   10 * time.Second,
+			deadline:       time.Minute,
+			lastBeatAgo:    30 * time.Second,
+			expectedStatus: CheckStatusWarn,
+			expectedError:  "consumer: no heartbeat for 30",
+		},
+		{
+			name:           "expired deadline with warn deadline",
+			warnDeadline:   10 * time.Second,
+			deadline:       time.Minute,
+			lastBeatAgo:    2 * time.Minute,
+			expectedStatus: CheckStatusFail,
+			expectedError:  "consumer: no heartbeat for 2m0",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			watchdog := NewHeartbeatWatchdog("workers")
+
+			handle := watchdog.RegisterWithWarnDeadline("consumer", testCase.warnDeadline, testCase.deadline)
+			setLastBeatAgo(handle, testCase.lastBeatAgo)
+
+			// healthy worker must not affect status of watchdog
+			watchdog.Register("producer", time.Minute)
+
+			result := watchdog.Check(context.Background())
+			if result.Status != testCase.expectedStatus {
+				t.Fatalf("expected status %s, got %s: %s", testCase.expectedStatus, result.Status, result.Error)
+			}
+
+			if !strings.HasPrefix(result.Error, testCase.expectedError) {
+				t.Fatalf("expected error with prefix %q, got %q", testCase.expectedError, result.Error)
+			}
+
+			if len(result.Checks) != 2 || result.Checks[0].Name != "consumer" || result.Checks[1].Name != "producer" {
+				t.Fatalf("expected sub-results of workers sorted by name, got %+v", result.Checks)
+			}
+		})
+	}
+}
+
+func TestHeartbeatWatchdog_BeatAndUnregister(t *testing.T) {
+	watchdog := NewHeartbeatWatchdog("workers")
+
+	handle := watchdog.Register("consumer", 50*time.Millisecond)
+
+	if !watchdog.IsHealed(context.Background()) {
+		t.Fatal("expected healthy watchdog right after registration")
+	}
+
+	time.Sleep(100 * time.Millisecond)
+
+	if watchdog.IsHealed(context.Background()) {
+		t.Fatal("expected failed watchdog after deadline expiry")
+	}
+
+	handle.Beat()
+
+	if !watchdog.IsHealed(context.Background()) {
+		t.Fatal("expected healthy watchdog after heartbeat")
+	}
+
+	setLastBeatAgo(handle, time.Minute)
+	handle.Unregister()
+
+	result := watchdog.Check(context.Background())
+	if result.Status != CheckStatusPass || len(result.Checks) != 0 {
+		t.Fatalf("expected passed watchdog without workers after unregister, got %s %+v", result.Status, result.Checks)
+	}
+}
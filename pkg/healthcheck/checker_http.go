@@ -0,0 +1,309 @@
+/*
+ *
+ *
+ * MIT NON-AI License
+ *
+ * Copyright (c) 2022-2024 Aleksei Kotelnikov(gudron2s@gmail.com)
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy of the software and associated documentation files (the "Software"),
+ * to deal in the Software without restriction, including without limitation the rights to use, copy, modify, merge, publish, distribute, sublicense,
+ * and/or sell copies of the Software, and to permit persons to whom the Software is furnished to do so, subject to the following conditions.
+ *
+ * The above copyright notice and this permission notice shall be included in all copies or substantial portions of the Software.
+ *
+ * In addition, the following restrictions apply:
+ *
+ * 1. The Software and any modifications made to it may not be used for the purpose of training or improving machine learning algorithms,
+ * including but not limited to artificial intelligence, natural language processing, or data mining. This condition applies to any derivatives,
+ * modifications, or updates based on the Software code. Any usage of the Software in an AI-training dataset is considered a breach of this License.
+ *
+ * 2. The Software may not be included in any dataset used for training or improving machine learning algorithms,
+ * including but not limited to artificial intelligence, natural language processing, or data mining.
+ *
+ * 3. Any person or organization found to be in violation of these restrictions will be subject to legal action and may be held liable
+ * for any damages resulting from such use.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM,
+ * DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE
+ * OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+ *
+ */
+
+package healthcheck
+
+import (
+	"bytes"
+	"context"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	ObservedStatusCodeKey    = "statusCode"
+	ObservedCertExpiresAtKey = "certExpiresAt"
+	ObservedCertExpiresInKey = "certExpiresIn"
+)
+
+const (
+	httpCheckerDefaultTimeout = 5 * time.Second
+	// httpCheckerMaxBodySize - max size of response body read for body conditions
+	httpCheckerMaxBodySize = 1 << 20
+)
+
+var ErrJSONPathNotFound = errors.New("json path not found")
+
+// HTTPCheckerOptions - options of http upstream checker. Zero values of optional options disable related checks
+type HTTPCheckerOptions struct {
+	// URL - requested url, required
+	URL string
+	// Method - http method, default - GET
+	Method string
+	// Headers - request headers, e.g. Authorization
+	Headers map[string]string
+	// Timeout - timeout of request, default - 5s
+	Timeout time.Duration
+	// HTTPClient - http client, e.g. with custom TLS config. New client without custom settings used by default
+	HTTPClient *http.Client
+	// ExpectedStatusCodes - expected response status codes, any 2xx status code by default
+	ExpectedStatusCodes []int
+	// BodyPattern - response body must match pattern
+	BodyPattern *regexp.Regexp
+	// JSONPath - dot separated path of value in json response body, e.g. data.items.0.status
+	JSONPath string
+	// JSONPathExpectedValue - expected value by JSONPath. Only presence of value checked if empty
+	JSONPathExpectedValue string
+	// CertExpiryWarn - warn if server certificate expires earlier than duration
+	CertExpiryWarn time.Duration
+	// CertExpiryFail - fail if server certificate expires earlier than duration
+	CertExpiryFail time.Duration
+}
+
+// httpChecker - probe unit of http upstream dependency, e.g. block explorer or readiness endpoint of other service
+type httpChecker struct {
+	CheckerBase
+
+	client  *http.Client
+	options HTTPCheckerOptions
+}
+
+func (c *httpChecker) Check(ctx context.Context) *CheckResult {
+	result := NewCheckResult(c.name)
+
+	err := c.check(ctx, result)
+	if err != nil {
+		result.Status = CheckStatusFail
+		result.Error = err.Error()
+	}
+
+	return result
+}
+
+func (c *httpChecker) check(ctx context.Context, result *CheckResult) error {
+	timeout := c.options.Timeout
+	if timeout <= 0 {
+		timeout = httpCheckerDefaultTimeout
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	method := c.options.Method
+	if method == "" {
+		method = http.MethodGet
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.options.URL, nil)
+	if err != nil {
+		return fmt.Errorf("unable to create request: %w", err)
+	}
+
+	for key, value := range c.options.Headers {
+		req.Header.Set(key, value)
+	}
+
+	startedAt := time.Now()
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("request failed: %w", err)
+	}
+
+	defer func() {
+		// rest of body drained, so keep-alive connection reused by next check
+		_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, httpCheckerMaxBodySize))
+		_ = resp.Body.Close()
+	}()
+
+	result.Observed[ObservedLatencyKey] = time.Since(startedAt).String()
+	result.Observed[ObservedStatusCodeKey] = resp.StatusCode
+
+	err = c.checkStatusCode(resp.StatusCode)
+	if err != nil {
+		return err
+	}
+
+	err = c.checkBody(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	return c.checkCertExpiry(resp, result)
+}
+
+func (c *httpChecker) checkStatusCode(statusCode int) error {
+	if len(c.options.ExpectedStatusCodes) == 0 {
+		if statusCode >= http.StatusOK && statusCode < http.StatusMultipleChoices {
+			return nil
+		}
+
+		return fmt.Errorf("unexpected status code: %d, expected 2xx", statusCode)
+	}
+
+	if slices.Contains(c.options.ExpectedStatusCodes, statusCode) {
+		return nil
+	}
+
+	return fmt.Errorf("unexpected status code: %d, expected one of: %v", statusCode, c.options.ExpectedStatusCodes)
+}
+
+func (c *httpChecker) checkBody(body io.Reader) error {
+	if c.options.BodyPattern == nil && c.options.JSONPath == "" {
+		return nil
+	}
+
+	data, err := io.ReadAll(io.LimitReader(body, httpCheckerMaxBodySize))
+	if err != nil {
+		return fmt.Errorf("unable to read response body: %w", err)
+	}
+
+	if c.options.BodyPattern != nil && !c.options.BodyPattern.Match(data) {
+		return fmt.Errorf("response body doesn't match pattern %q", c.options.BodyPattern.String())
+	}
+
+	if c.options.JSONPath == "" {
+		return nil
+	}
+
+	value, err := lookupJSONPath(data, c.options.JSONPath)
+	if err != nil {
+		return err
+	}
+
+	if c.options.JSONPathExpectedValue != "" && value != c.options.JSONPathExpectedValue {
+		return fmt.Errorf("unexpected value by json path %s: %q, expected: %q",
+			c.options.JSONPath, value, c.options.JSONPathExpectedValue)
+	}
+
+	return nil
+}
+
+func (c *httpChecker) checkCertExpiry(resp *http.Response, result *CheckResult) error {
+	if resp.TLS == nil || len(resp.TLS.PeerCertificates) == 0 {
+		return nil
+	}
+
+	return checkCertificateExpiry(resp.TLS.PeerCertificates[0],
+		c.options.CertExpiryWarn, c.options.CertExpiryFail, result)
+}
+
+// checkCertificateExpiry - add certificate expiry to observed values of result.
+// Returns error if certificate expires earlier than fail duration, set warn status if earlier than warn duration
+func checkCertificateExpiry(cert *x509.Certificate, warnBefore, failBefore time.Duration, result *CheckResult) error {
+	expiresAt := cert.NotAfter
+	expiresIn := time.Until(expiresAt)
+
+	result.Observed[ObservedCertExpiresAtKey] = expiresAt
+	result.Observed[ObservedCertExpiresInKey] = expiresIn.Truncate(time.Second).String()
+
+	switch {
+	case failBefore > 0 && expiresIn < failBefore:
+		return fmt.Errorf("server certificate expires in %s", expiresIn.Truncate(time.Second))
+	case warnBefore > 0 && expiresIn < warnBefore:
+		result.Status = CheckStatusWarn
+		result.Error = fmt.Sprintf("server certificate expires in %s", expiresIn.Truncate(time.Second))
+	}
+
+	return nil
+}
+
+// lookupJSONPath - returns value by dot separated path in json document, array elements addressed by index.
+// Non-string values returned in json encoding
+func lookupJSONPath(data []byte, path string) (string, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	var value any
+
+	err := decoder.Decode(&value)
+	if err != nil {
+		return "", fmt.Errorf("unable to unmarshal json response body: %w", err)
+	}
+
+	for _, key := range strings.Split(path, ".") {
+		switch node := value.(type) {
+		case map[string]any:
+			child, isExists := node[key]
+			if !isExists {
+				return "", fmt.Errorf("%w: %s", ErrJSONPathNotFound, path)
+			}
+
+			value = child
+		case []any:
+			index, parseErr := strconv.Atoi(key)
+			if parseErr != nil || index < 0 || index >= len(node) {
+				return "", fmt.Errorf("%w: %s", ErrJSONPathNotFound, path)
+			}
+
+			value = node[index]
+		default:
+			return "", fmt.Errorf("%w: %s", ErrJSONPathNotFound, path)
+		}
+	}
+
+	stringValue, isString := value.(string)
+	if isString {
+		return stringValue, nil
+	}
+
+	encodedValue, err := json.Marshal(value)
+	if err != nil {
+		return "", fmt.Errorf("unable to marshal value by json path: %w", err)
+	}
+
+	return string(encodedValue), nil
+}
+
+// NewHTTPChecker - probe unit of http upstream dependency. Checks status code, optional body conditions
+// and server certificate expiry, reports response time. Returns error if url not set in options
+func NewHTTPChecker(name string, options *HTTPCheckerOptions) (*httpChecker, error) {
+	if options == nil || options.URL == "" {
+		return nil, fmt.Errorf("%w: url of http checker %s", ErrCheckerOptionRequired, name)
+	}
+
+	client := options.HTTPClient
+	if client == nil {
+		//nolint:exhaustruct // it's ok here. timeout of request set by context
+		client = &http.Client{}
+	}
+
+	checker := &httpChecker{
+		CheckerBase: CheckerBase{name: name, checkFunc: nil},
+
+		client:  client,
+		options: *options,
+	}
+
+	checker.checkFunc = checker.Check
+
+	return checker, nil
+}
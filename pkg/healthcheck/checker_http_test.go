@@ -0,0 +1,226 @@
+/*
+ *
+ *
+ * MIT NON-AI License
+ *
+ * Copyright (c) 2022-2024 Aleksei Kotelnikov(gudron2s@gmail.com)
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy of the software and associated documentation files (the "Software"),
+ * to deal in the Software without restriction, including without limitation the rights to use, copy, modify, merge, publish, distribute, sublicense,
+ * and/or sell copies of the Software, and to permit persons to whom the Software is furnished to do so, subject to the following conditions.
+ *
+ * The above copyright notice and this permission notice shall be included in all copies or substantial portions of the Software.
+ *
+ * In addition, the following restrictions apply:
+ *
+ * 1. The Software and any modifications made to it may not be used for the purpose of training or improving machine learning algorithms,
+ * including but not limited to artificial intelligence, natural language processing, or data mining. This condition applies to any derivatives,
+ * modifications, or updates based on the Software code. Any usage of the Software in an AI-training dataset is considered a breach of this License.
+ *
+ * 2. The Software may not be included in any dataset used for training or improving machine learning algorithms,
+ * including but not limited to artificial intelligence, natural language processing, or data mining.
+ *
+ * 3. Any person or organization found to be in violation of these restrictions will be subject to legal action and may be held liable
+ * for any damages resulting from such use.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM,
+ * DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE
+ * OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+ *
+ */
+package healthcheck
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestHTTPChecker(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/status", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = io.WriteString(w, `{"data":{"status":"ok","items":[{"height":100}]}}`)
+	})
+	mux.HandleFunc("/unavailable", func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})
+	mux.HandleFunc("/auth", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodHead || r.Header.Get("Authorization") != "Bearer token" {
+			w.WriteHeader(http.StatusUnauthorized)
+
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	tlsServer := httptest.NewTLSServer(mux)
+	t.Cleanup(tlsServer.Close)
+
+	testCases := []struct {
+		name           string
+		options        HTTPCheckerOptions
+		expectedStatus CheckStatus
+	}{
+		{
+			name:           "2xx status code",
+			options:        HTTPCheckerOptions{URL: server.URL + "/status"}, //nolint:exhaustruct // defaults
+			expectedStatus: CheckStatusPass,
+		},
+		{
+			name:           "unexpected status code",
+			options:        HTTPCheckerOptions{URL: server.URL + "/unavailable"}, //nolint:exhaustruct // defaults
+			expectedStatus: CheckStatusFail,
+		},
+		{
+			name: "expected status code",
+			//nolint:exhaustruct // only tested options
+			options: HTTPCheckerOptions{
+				URL:                 server.URL + "/unavailable",
+				ExpectedStatusCodes: []int{http.StatusServiceUnavailable},
+			},
+			expectedStatus: CheckStatusPass,
+		},
+		{
+			name: "method and headers",
+			//nolint:exhaustruct // only tested options
+			options: HTTPCheckerOptions{
+				URL:     server.URL + "/auth",
+				Method:  http.MethodHead,
+				Headers: map[string]string{"Authorization": "Bearer token"},
+			},
+			expectedStatus: CheckStatusPass,
+		},
+		{
+			name: "body pattern mismatch",
+			//nolint:exhaustruct // only tested options
+			options: HTTPCheckerOptions{
+				URL:         server.URL + "/status",
+				BodyPattern: regexp.MustCompile(`"status":"failed"`),
+			},
+			expectedStatus: CheckStatusFail,
+		},
+		{
+			name: "json path value",
+			//nolint:exhaustruct // only tested options
+			options: HTTPCheckerOptions{
+				URL:                   server.URL + "/status",
+				JSONPath:              "data.items.0.height",
+				JSONPathExpectedValue: "100",
+			},
+			expectedStatus: CheckStatusPass,
+		},
+		{
+			name: "json path not found",
+			//nolint:exhaustruct // only tested options
+			options: HTTPCheckerOptions{
+				URL:      server.URL + "/status",
+				JSONPath: "data.items.1.height",
+			},
+			expectedStatus: CheckStatusFail,
+		},
+		{
+			name: "certificate expiry warn",
+			//nolint:exhaustruct // only tested options
+			options: HTTPCheckerOptions{
+				URL:            tlsServer.URL + "/status",
+				HTTPClient:     tlsServer.Client(),
+				Timeout:        time.Second,
+				CertExpiryWarn: 200 * 365 * 24 * time.Hour,
+			},
+			expectedStatus: CheckStatusWarn,
+		},
+		{
+			name: "certificate expiry fail",
+			//nolint:exhaustruct // only tested options
+			options: HTTPCheckerOptions{
+				URL:            tlsServer.URL + "/status",
+				HTTPClient:     tlsServer.Client(),
+				CertExpiryFail: 200 * 365 * 24 * time.Hour,
+			},
+			expectedStatus: CheckStatusFail,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			checker, err := NewHTTPChecker("upstream", &testCase.options)
+			if err != nil {
+				t.Fatalf("unable to create checker: %s", err)
+			}
+
+			result := checker.Check(context.Background())
+			if result.Status != testCase.expectedStatus {
+				t.Fatalf("unexpected status: %s, expected: %s, error: %s",
+					result.Status, testCase.expectedStatus, result.Error)
+			}
+		})
+	}
+}
+
+func TestHTTPCheckerRequiredURL(t *testing.T) {
+	_, err := NewHTTPChecker("upstream", nil)
+	if !errors.Is(err, ErrCheckerOptionRequired) {
+		t.Fatalf("unexpected error on nil options: %v", err)
+	}
+
+	_, err = NewHTTPChecker("upstream", &HTTPCheckerOptions{}) //nolint:exhaustruct // url not set
+	if !errors.Is(err, ErrCheckerOptionRequired) {
+		t.Fatalf("unexpected error without url: %v", err)
+	}
+}
+
+func TestHTTPCheckerConnectionReuse(t *testing.T) {
+	// body larger than read buffers of http transport, so not drained body closes connection
+	body := strings.Repeat("x", 512<<10)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/status", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = io.WriteString(w, body)
+	})
+	mux.HandleFunc("/unavailable", func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		_, _ = io.WriteString(w, body)
+	})
+
+	newConnections := atomic.Int64{}
+
+	server := httptest.NewUnstartedServer(mux)
+	server.Config.ConnState = func(_ net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			newConnections.Add(1)
+		}
+	}
+	server.Start()
+	t.Cleanup(server.Close)
+
+	for _, path := range []string{"/status", "/unavailable", "/status", "/status"} {
+		//nolint:exhaustruct // only tested options, body conditions not set
+		checker, err := NewHTTPChecker("upstream", &HTTPCheckerOptions{
+			URL:        server.URL + path,
+			HTTPClient: server.Client(),
+		})
+		if err != nil {
+			t.Fatalf("unable to create checker: %s", err)
+		}
+
+		checker.Check(context.Background())
+	}
+
+	if newConnections.Load() != 1 {
+		t.Fatalf("expected one reused connection, got %d connections", newConnections.Load())
+	}
+}
@@ -0,0 +1,194 @@
+/*
+ *
+ *
+ * MIT NON-AI License
+ *
+ * Copyright (c) 2022-2024 Aleksei Kotelnikov(gudron2s@gmail.com)
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy of the software and associated documentation files (the "Software"),
+ * to deal in the Software without restriction, including without limitation the rights to use, copy, modify, merge, publish, distribute, sublicense,
+ * and/or sell copies of the Software, and to permit persons to whom the Software is furnished to do so, subject to the following conditions.
+ *
+ * The above copyright notice and this permission notice shall be included in all copies or substantial portions of the Software.
+ *
+ * In addition, the following restrictions apply:
+ *
+ * 1. The Software and any modifications made to it may not be used for the purpose of training or improving machine learning algorithms,
+ * including but not limited to artificial intelligence, natural language processing, or data mining. This condition applies to any derivatives,
+ * modifications, or updates based on the Software code. Any usage of the Software in an AI-training dataset is considered a breach of this License.
+ *
+ * 2. The Software may not be included in any dataset used for training or improving machine learning algorithms,
+ * including but not limited to artificial intelligence, natural language processing, or data mining.
+ *
+ * 3. Any person or organization found to be in violation of these restrictions will be subject to legal action and may be held liable
+ * for any damages resulting from such use.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM,
+ * DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE
+ * OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+ *
+ */
+package healthcheck
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"testing"
+	"time"
+)
+
+// startFakeNATSServer - nats protocol stand-in of nats server. Sends INFO message, accepts CONNECT message
+// with expected token and replies PONG on PING
+func startFakeNATSServer(t *testing.T, info natsServerInfo, token string) string {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("unable to listen: %s", err)
+	}
+
+	t.Cleanup(func() {
+		_ = listener.Close()
+	})
+
+	infoPayload, err := json.Marshal(info)
+	if err != nil {
+		t.Fatalf("unable to marshal info: %s", err)
+	}
+
+	go func() {
+		for {
+			conn, acceptErr := listener.Accept()
+			if acceptErr != nil {
+				return
+			}
+
+			go serveFakeNATSConn(conn, string(infoPayload), token)
+		}
+	}()
+
+	return listener.Addr().String()
+}
+
+func serveFakeNATSConn(conn net.Conn, infoPayload, token string) {
+	defer func() {
+		_ = conn.Close()
+	}()
+
+	_, err := io.WriteString(conn, "INFO "+infoPayload+"\r\n")
+	if err != nil {
+		return
+	}
+
+	reader := bufio.NewReader(conn)
+
+	for {
+		line, readErr := reader.ReadString('\n')
+		if readErr != nil {
+			return
+		}
+
+		line = strings.TrimRight(line, "\r\n")
+
+		switch {
+		case strings.HasPrefix(line, "CONNECT "):
+			connectOptions := natsConnectOptions{} //nolint:exhaustruct // filled by unmarshal
+
+			_ = json.Unmarshal([]byte(strings.TrimPrefix(line, "CONNECT ")), &connectOptions)
+			if connectOptions.AuthToken != token {
+				_, _ = io.WriteString(conn, "-ERR 'Authorization Violation'\r\n")
+
+				return
+			}
+		case line == "PING":
+			_, _ = io.WriteString(conn, "+OK\r\nPONG\r\n")
+		}
+	}
+}
+
+func TestNATSChecker(t *testing.T) {
+	testCases := []struct {
+		name             string
+		isJetStream      bool
+		serverToken      string
+		clientToken      string
+		requireJetStream bool
+		expectedStatus   CheckStatus
+	}{
+		{
+			name:             "handshake",
+			isJetStream:      false,
+			serverToken:      "",
+			clientToken:      "",
+			requireJetStream: false,
+			expectedStatus:   CheckStatusPass,
+		},
+		{
+			name:             "token authentication",
+			isJetStream:      true,
+			serverToken:      "secret",
+			clientToken:      "secret",
+			requireJetStream: true,
+			expectedStatus:   CheckStatusPass,
+		},
+		{
+			name:             "authorization violation",
+			isJetStream:      false,
+			serverToken:      "secret",
+			clientToken:      "wrong",
+			requireJetStream: false,
+			expectedStatus:   CheckStatusFail,
+		},
+		{
+			name:             "jetstream disabled",
+			isJetStream:      false,
+			serverToken:      "",
+			clientToken:      "",
+			requireJetStream: true,
+			expectedStatus:   CheckStatusFail,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			address := startFakeNATSServer(t, natsServerInfo{
+				ServerID:     "fake",
+				Version:      "2.10.0",
+				TLSRequired:  false,
+				AuthRequired: testCase.serverToken != "",
+				JetStream:    testCase.isJetStream,
+			}, testCase.serverToken)
+
+			result := NewNATSChecker("nats", &NATSCheckerOptions{
+				URL:              fmt.Sprintf("nats://%s", address),
+				Username:         "",
+				Password:         "",
+				Token:            testCase.clientToken,
+				TLSConfig:        nil,
+				Timeout:          time.Second,
+				RequireJetStream: testCase.requireJetStream,
+			}).Check(context.Background())
+			if result.Status != testCase.expectedStatus {
+				t.Fatalf("unexpected status: %s, expected: %s, error: %s",
+					result.Status, testCase.expectedStatus, result.Error)
+			}
+
+			if result.Observed[ObservedServerIDKey] != "fake" {
+				t.Fatalf("unexpected server id: %v", result.Observed[ObservedServerIDKey])
+			}
+		})
+	}
+}
+
+func TestNATSCheckerNilOptions(t *testing.T) {
+	checker := NewNATSChecker("nats", nil)
+	if checker.options.URL != natsDefaultURL {
+		t.Fatalf("unexpected default url: %q", checker.options.URL)
+	}
+}
@@ -0,0 +1,189 @@
+/*
+ *
+ *
+ * MIT NON-AI License
+ *
+ * Copyright (c) 2022-2024 Aleksei Kotelnikov(gudron2s@gmail.com)
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy of the software and associated documentation files (the "Software"),
+ * to deal in the Software without restriction, including without limitation the rights to use, copy, modify, merge, publish, distribute, sublicense,
+ * and/or sell copies of the Software, and to permit persons to whom the Software is furnished to do so, subject to the following conditions.
+ *
+ * The above copyright notice and this permission notice shall be included in all copies or substantial portions of the Software.
+ *
+ * In addition, the following restrictions apply:
+ *
+ * 1. The Software and any modifications made to it may not be used for the purpose of training or improving machine learning algorithms,
+ * including but not limited to artificial intelligence, natural language processing, or data mining. This condition applies to any derivatives,
+ * modifications, or updates based on the Software code. Any usage of the Software in an AI-training dataset is considered a breach of this License.
+ *
+ * 2. The Software may not be included in any dataset used for training or improving machine learning algorithms,
+ * including but not limited to artificial intelligence, natural language processing, or data mining.
+ *
+ * 3. Any person or organization found to be in violation of these restrictions will be subject to legal action and may be held liable
+ * for any damages resulting from such use.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM,
+ * DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE
+ * OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+ *
+ */
+
+package healthcheck
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+const (
+	ObservedRoleKey              = "role"
+	ObservedReplayLagKey         = "replayLag"
+	ObservedWALReceiverStatusKey = "walReceiverStatus"
+)
+
+const (
+	postgresRolePrimary = "primary"
+	postgresRoleReplica = "replica"
+
+	postgresWALReceiverStatusStreaming = "streaming"
+	// postgresWALReceiverStatusStopped - status of replica without wal receiver process
+	postgresWALReceiverStatusStopped = "stopped"
+	// postgresWALReceiverStatusUnknown - status of wal receiver hidden from role without pg_read_all_stats privilege
+	postgresWALReceiverStatusUnknown = "unknown"
+
+	// postgresReplicationQuery - recovery flag, existence and status of wal receiver, equality of received
+	// and replayed lsn and time since last replayed transaction in seconds.
+	// Status of wal receiver is NULL for role without pg_read_all_stats privilege
+	postgresReplicationQuery = `SELECT pg_is_in_recovery(),
+	EXISTS (SELECT 1 FROM pg_stat_wal_receiver),
+	(SELECT status FROM pg_stat_wal_receiver LIMIT 1),
+	pg_last_wal_receive_lsn() = pg_last_wal_replay_lsn(),
+	EXTRACT(EPOCH FROM now() - pg_last_xact_replay_timestamp())`
+)
+
+// PostgresReplicationCheckerOptions - options of postgres replication checker.
+// Zero values of options disable related checks
+type PostgresReplicationCheckerOptions struct {
+	// RequirePrimary - fail if database is replica, e.g. for writer pods
+	RequirePrimary bool
+	// RequireStreaming - fail if wal receiver of replica isn't streaming. By default replica with not streaming
+	// wal receiver only warns, e.g. replica restored from wal archive. Unknown status of wal receiver not checked
+	RequireStreaming bool
+	// WarnReplayLag - warn if replay lag of replica exceeds value
+	WarnReplayLag time.Duration
+	// MaxReplayLag - fail if replay lag of replica exceeds value
+	MaxReplayLag time.Duration
+	// Timeout - timeout of replication query
+	Timeout time.Duration
+}
+
+// postgresReplicationChecker - probe unit of postgres replication role and replay lag
+type postgresReplicationChecker struct {
+	CheckerBase
+
+	db      sqlQuerierService
+	options PostgresReplicationCheckerOptions
+}
+
+func (c *postgresReplicationChecker) Check(ctx context.Context) *CheckResult {
+	result := NewCheckResult(c.name)
+
+	if c.options.Timeout > 0 {
+		var cancel context.CancelFunc
+
+		ctx, cancel = context.WithTimeout(ctx, c.options.Timeout)
+		defer cancel()
+	}
+
+	var (
+		isInRecovery         bool
+		isWALReceiverExists  bool
+		walReceiverStatus    sql.NullString
+		isReplayedAllWAL     sql.NullBool
+		sinceLastReplayInSec sql.NullFloat64
+	)
+
+	err := c.db.QueryRowContext(ctx, postgresReplicationQuery).Scan(&isInRecovery, &isWALReceiverExists,
+		&walReceiverStatus, &isReplayedAllWAL, &sinceLastReplayInSec)
+	if err != nil {
+		result.Status = CheckStatusFail
+		result.Error = fmt.Sprintf("replication query failed: %s", err)
+
+		return result
+	}
+
+	if !isInRecovery {
+		result.Observed[ObservedRoleKey] = postgresRolePrimary
+
+		return result
+	}
+
+	// replica which replayed all received wal has no lag, even if primary is idle and time
+	// of last replayed transaction is far in the past
+	var replayLag time.Duration
+	if !isReplayedAllWAL.Valid || !isReplayedAllWAL.Bool {
+		replayLag = time.Duration(sinceLastReplayInSec.Float64 * float64(time.Second))
+	}
+
+	status := postgresWALReceiverStatusStopped
+	if isWALReceiverExists {
+		status = postgresWALReceiverStatusUnknown
+		if walReceiverStatus.Valid {
+			status = walReceiverStatus.String
+		}
+	}
+
+	result.Observed[ObservedRoleKey] = postgresRoleReplica
+	result.Observed[ObservedReplayLagKey] = replayLag.String()
+	result.Observed[ObservedWALReceiverStatusKey] = status
+
+	// unknown status is not treated as not streaming
+	isNotStreaming := status != postgresWALReceiverStatusStreaming && status != postgresWALReceiverStatusUnknown
+
+	switch {
+	case c.options.RequirePrimary:
+		result.Status = CheckStatusFail
+		result.Error = "connected to replica, primary required"
+	case c.options.RequireStreaming && isNotStreaming:
+		result.Status = CheckStatusFail
+		result.Error = fmt.Sprintf("wal receiver is not streaming, status: %q", status)
+	case c.options.MaxReplayLag > 0 && replayLag > c.options.MaxReplayLag:
+		result.Status = CheckStatusFail
+		result.Error = fmt.Sprintf("replay lag %s exceeds max lag %s", replayLag, c.options.MaxReplayLag)
+	case c.options.WarnReplayLag > 0 && replayLag > c.options.WarnReplayLag:
+		result.Status = CheckStatusWarn
+		result.Error = fmt.Sprintf("replay lag %s exceeds warn lag %s", replayLag, c.options.WarnReplayLag)
+	case isNotStreaming:
+		result.Status = CheckStatusWarn
+		result.Error = fmt.Sprintf("wal receiver is not streaming, status: %q", status)
+	}
+
+	return result
+}
+
+// NewPostgresReplicationChecker - probe unit of postgres replication role and replay lag, e.g. by *sql.DB.
+// Options are optional. Role of connection requires pg_read_all_stats privilege, e.g. by pg_monitor role,
+// for read of wal receiver status, otherwise status reported as unknown and not checked
+func NewPostgresReplicationChecker(name string,
+	db sqlQuerierService,
+	options *PostgresReplicationCheckerOptions,
+) *postgresReplicationChecker {
+	checker := &postgresReplicationChecker{
+		CheckerBase: CheckerBase{name: name, checkFunc: nil},
+
+		db:      db,
+		options: PostgresReplicationCheckerOptions{},
+	}
+
+	if options != nil {
+		checker.options = *options
+	}
+
+	checker.checkFunc = checker.Check
+
+	return checker
+}
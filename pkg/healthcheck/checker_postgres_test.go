@@ -0,0 +1,231 @@
+/*
+ *
+ *
+ * MIT NON-AI License
+ *
+ * Copyright (c) 2022-2024 Aleksei Kotelnikov(gudron2s@gmail.com)
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy of the software and associated documentation files (the "Software"),
+ * to deal in the Software without restriction, including without limitation the rights to use, copy, modify, merge, publish, distribute, sublicense,
+ * and/or sell copies of the Software, and to permit persons to whom the Software is furnished to do so, subject to the following conditions.
+ *
+ * The above copyright notice and this permission notice shall be included in all copies or substantial portions of the Software.
+ *
+ * In addition, the following restrictions apply:
+ *
+ * 1. The Software and any modifications made to it may not be used for the purpose of training or improving machine learning algorithms,
+ * including but not limited to artificial intelligence, natural language processing, or data mining. This condition applies to any derivatives,
+ * modifications, or updates based on the Software code. Any usage of the Software in an AI-training dataset is considered a breach of this License.
+ *
+ * 2. The Software may not be included in any dataset used for training or improving machine learning algorithms,
+ * including but not limited to artificial intelligence, natural language processing, or data mining.
+ *
+ * 3. Any person or organization found to be in violation of these restrictions will be subject to legal action and may be held liable
+ * for any damages resulting from such use.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM,
+ * DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE
+ * OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+ *
+ */
+package healthcheck
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"testing"
+	"time"
+)
+
+// newPostgresReplicationRow - row of replication query: recovery flag, existence and status of wal receiver,
+// equality of received and replayed lsn, time since last replayed transaction
+func newPostgresReplicationRow(isInRecovery, isReceiverExists bool,
+	receiverStatus, isReplayedAllWAL, sinceLastReplay driver.Value,
+) *fakeSQLDatabase {
+	return &fakeSQLDatabase{
+		pingErr:  nil,
+		queryErr: nil,
+		row:      []driver.Value{isInRecovery, isReceiverExists, receiverStatus, isReplayedAllWAL, sinceLastReplay},
+	}
+}
+
+func TestPostgresReplicationChecker(t *testing.T) {
+	lagOptions := &PostgresReplicationCheckerOptions{
+		RequirePrimary:   false,
+		RequireStreaming: false,
+		WarnReplayLag:    10 * time.Second,
+		MaxReplayLag:     time.Minute,
+		Timeout:          0,
+	}
+	streamingOptions := &PostgresReplicationCheckerOptions{
+		RequirePrimary:   false,
+		RequireStreaming: true,
+		WarnReplayLag:    0,
+		MaxReplayLag:     0,
+		Timeout:          0,
+	}
+
+	testCases := []struct {
+		name              string
+		db                *fakeSQLDatabase
+		options           *PostgresReplicationCheckerOptions
+		expectedStatus    CheckStatus
+		expectedRole      string
+		expectedWALStatus string
+		expectedLag       string
+	}{
+		{
+			name:              "primary",
+			db:                newPostgresReplicationRow(false, false, nil, nil, nil),
+			options:           nil,
+			expectedStatus:    CheckStatusPass,
+			expectedRole:      postgresRolePrimary,
+			expectedWALStatus: "",
+			expectedLag:       "",
+		},
+		{
+			name: "replica, primary required",
+			db:   newPostgresReplicationRow(true, true, "streaming", true, 0.0),
+			options: &PostgresReplicationCheckerOptions{
+				RequirePrimary:   true,
+				RequireStreaming: false,
+				WarnReplayLag:    0,
+				MaxReplayLag:     0,
+				Timeout:          time.Second,
+			},
+			expectedStatus:    CheckStatusFail,
+			expectedRole:      postgresRoleReplica,
+			expectedWALStatus: "streaming",
+			expectedLag:       "0s",
+		},
+		{
+			name:              "streaming replica without lag",
+			db:                newPostgresReplicationRow(true, true, "streaming", true, 0.0),
+			options:           nil,
+			expectedStatus:    CheckStatusPass,
+			expectedRole:      postgresRoleReplica,
+			expectedWALStatus: "streaming",
+			expectedLag:       "0s",
+		},
+		{
+			name:              "replica of idle primary",
+			db:                newPostgresReplicationRow(true, true, "streaming", true, 3600.0),
+			options:           lagOptions,
+			expectedStatus:    CheckStatusPass,
+			expectedRole:      postgresRoleReplica,
+			expectedWALStatus: "streaming",
+			expectedLag:       "0s",
+		},
+		{
+			name:              "streaming replica with warn lag",
+			db:                newPostgresReplicationRow(true, true, "streaming", false, 15.0),
+			options:           lagOptions,
+			expectedStatus:    CheckStatusWarn,
+			expectedRole:      postgresRoleReplica,
+			expectedWALStatus: "streaming",
+			expectedLag:       "15s",
+		},
+		{
+			name:              "streaming replica with max lag",
+			db:                newPostgresReplicationRow(true, true, "streaming", false, 90.0),
+			options:           lagOptions,
+			expectedStatus:    CheckStatusFail,
+			expectedRole:      postgresRoleReplica,
+			expectedWALStatus: "streaming",
+			expectedLag:       "1m30s",
+		},
+		{
+			name:              "replica without wal receiver",
+			db:                newPostgresReplicationRow(true, false, nil, nil, 0.0),
+			options:           nil,
+			expectedStatus:    CheckStatusWarn,
+			expectedRole:      postgresRoleReplica,
+			expectedWALStatus: postgresWALReceiverStatusStopped,
+			expectedLag:       "0s",
+		},
+		{
+			name:              "replica with waiting wal receiver, streaming required",
+			db:                newPostgresReplicationRow(true, true, "waiting", true, 0.0),
+			options:           streamingOptions,
+			expectedStatus:    CheckStatusFail,
+			expectedRole:      postgresRoleReplica,
+			expectedWALStatus: "waiting",
+			expectedLag:       "0s",
+		},
+		{
+			name:              "replica without wal receiver and max lag",
+			db:                newPostgresReplicationRow(true, false, nil, nil, 90.0),
+			options:           lagOptions,
+			expectedStatus:    CheckStatusFail,
+			expectedRole:      postgresRoleReplica,
+			expectedWALStatus: postgresWALReceiverStatusStopped,
+			expectedLag:       "1m30s",
+		},
+		{
+			name:              "null wal receiver status",
+			db:                newPostgresReplicationRow(true, true, nil, true, 0.0),
+			options:           nil,
+			expectedStatus:    CheckStatusPass,
+			expectedRole:      postgresRoleReplica,
+			expectedWALStatus: postgresWALReceiverStatusUnknown,
+			expectedLag:       "0s",
+		},
+		{
+			name:              "null wal receiver status, streaming required",
+			db:                newPostgresReplicationRow(true, true, nil, true, 0.0),
+			options:           streamingOptions,
+			expectedStatus:    CheckStatusPass,
+			expectedRole:      postgresRoleReplica,
+			expectedWALStatus: postgresWALReceiverStatusUnknown,
+			expectedLag:       "0s",
+		},
+		{
+			name:              "null wal receiver status with max lag",
+			db:                newPostgresReplicationRow(true, true, nil, false, 90.0),
+			options:           lagOptions,
+			expectedStatus:    CheckStatusFail,
+			expectedRole:      postgresRoleReplica,
+			expectedWALStatus: postgresWALReceiverStatusUnknown,
+			expectedLag:       "1m30s",
+		},
+		{
+			name:              "query failed",
+			db:                &fakeSQLDatabase{pingErr: nil, queryErr: errors.New("permission denied"), row: nil},
+			options:           nil,
+			expectedStatus:    CheckStatusFail,
+			expectedRole:      "",
+			expectedWALStatus: "",
+			expectedLag:       "",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			checker := NewPostgresReplicationChecker("postgres", openFakeSQLDatabase(t, testCase.db),
+				testCase.options)
+
+			result := checker.Check(context.Background())
+			if result.Status != testCase.expectedStatus {
+				t.Fatalf("unexpected status: %s, expected: %s, error: %s",
+					result.Status, testCase.expectedStatus, result.Error)
+			}
+
+			role, _ := result.Observed[ObservedRoleKey].(string)
+			if role != testCase.expectedRole {
+				t.Fatalf("unexpected role: %q, expected: %q", role, testCase.expectedRole)
+			}
+
+			walStatus, _ := result.Observed[ObservedWALReceiverStatusKey].(string)
+			if walStatus != testCase.expectedWALStatus {
+				t.Fatalf("unexpected wal receiver status: %q, expected: %q", walStatus, testCase.expectedWALStatus)
+			}
+
+			lag, _ := result.Observed[ObservedReplayLagKey].(string)
+			if lag != testCase.expectedLag {
+				t.Fatalf("unexpected replay lag: %q, expected: %q", lag, testCase.expectedLag)
+			}
+		})
+	}
+}
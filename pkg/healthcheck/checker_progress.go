@@ -0,0 +1,136 @@
+/*
+ *
+ *
+ * MIT NON-AI License
+ *
+ * Copyright (c) 2022-2024 Aleksei Kotelnikov(gudron2s@gmail.com)
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy of the software and associated documentation files (the "Software"),
+ * to deal in the Software without restriction, including without limitation the rights to use, copy, modify, merge, publish, distribute, sublicense,
+ * and/or sell copies of the Software, and to permit persons to whom the Software is furnished to do so, subject to the following conditions.
+ *
+ * The above copyright notice and this permission notice shall be included in all copies or substantial portions of the Software.
+ *
+ * In addition, the following restrictions apply:
+ *
+ * 1. The Software and any modifications made to it may not be used for the purpose of training or improving machine learning algorithms,
+ * including but not limited to artificial intelligence, natural language processing, or data mining. This condition applies to any derivatives,
+ * modifications, or updates based on the Software code. Any usage of the Software in an AI-training dataset is considered a breach of this License.
+ *
+ * 2. The Software may not be included in any dataset used for training or improving machine learning algorithms,
+ * including but not limited to artificial intelligence, natural language processing, or data mining.
+ *
+ * 3. Any person or organization found to be in violation of these restrictions will be subject to legal action and may be held liable
+ * for any damages resulting from such use.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM,
+ * DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE
+ * OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+ *
+ */
+
+package healthcheck
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	ObservedValueKey         = "value"
+	ObservedLastAdvanceAtKey = "lastAdvanceAt"
+	ObservedStallDurationKey = "stallDuration"
+)
+
+// progressChecker - probe unit which fails if watched value not advanced within window,
+// e.g. last processed block height of blocks scanner
+type progressChecker struct {
+	CheckerBase
+
+	window    time.Duration
+	valueFunc func(ctx context.Context) (uint64, error)
+
+	isInitialized bool
+	lastValue     uint64
+	lastAdvanceAt time.Time
+
+	mu sync.Mutex
+}
+
+// Check - get current value and compare it with last advanced value. Time of first check is time of first advance.
+// Decreased value, e.g. after counter reset, becomes new baseline without advance
+func (c *progressChecker) Check(ctx context.Context) *CheckResult {
+	result := NewCheckResult(c.name)
+
+	value, err := c.valueFunc(ctx)
+	if err != nil {
+		result.Status = CheckStatusFail
+		result.Error = fmt.Sprintf("unable to get progress value: %s", err)
+
+		return result
+	}
+
+	now := time.Now()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if !c.isInitialized || value > c.lastValue {
+		c.isInitialized = true
+		c.lastAdvanceAt = now
+	}
+
+	// decreased value, e.g. after counter reset, stored as new baseline
+	c.lastValue = value
+	stallDuration := now.Sub(c.lastAdvanceAt)
+
+	result.Observed = map[string]any{
+		ObservedValueKey:         value,
+		ObservedLastAdvanceAtKey: c.lastAdvanceAt,
+		ObservedStallDurationKey: stallDuration.String(),
+	}
+
+	if stallDuration > c.window {
+		result.Status = CheckStatusFail
+		result.Error = fmt.Sprintf("no progress for %s, window %s, value %d",
+			stallDuration.Truncate(time.Millisecond), c.window, value)
+	}
+
+	return result
+}
+
+// NewProgressChecker - probe unit which watches value returned by valueFunc
+// and fails if value hasn't advanced within window
+func NewProgressChecker(name string,
+	window time.Duration,
+	valueFunc func(ctx context.Context) (uint64, error),
+) *progressChecker {
+	checker := &progressChecker{
+		CheckerBase: CheckerBase{name: name, checkFunc: nil},
+
+		window:    window,
+		valueFunc: valueFunc,
+
+		isInitialized: false,
+		lastValue:     0,
+		lastAdvanceAt: time.Time{},
+
+		mu: sync.Mutex{},
+	}
+
+	checker.checkFunc = checker.Check
+
+	return checker
+}
+
+// NewCounterProgressChecker - probe unit which watches monotonic counter
+// and fails if counter hasn't advanced within window
+func NewCounterProgressChecker(name string, window time.Duration, counter *atomic.Uint64) *progressChecker {
+	return NewProgressChecker(name, window, func(_ context.Context) (uint64, error) {
+		return counter.Load(), nil
+	})
+}
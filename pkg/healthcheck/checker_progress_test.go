@@ -0,0 +1,167 @@
+/*
+ *
+ *
+ * MIT NON-AI License
+ *
+ * Copyright (c) 2022-2024 Aleksei Kotelnikov(gudron2s@gmail.com)
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy of the software and associated documentation files (the "Software"),
+ * to deal in the Software without restriction, including without limitation the rights to use, copy, modify, merge, publish, distribute, sublicense,
+ * and/or sell copies of the Software, and to permit persons to whom the Software is furnished to do so, subject to the following conditions.
+ *
+ * The above copyright notice and this permission notice shall be included in all copies or substantial portions of the Software.
+ *
+ * In addition, the following restrictions apply:
+ *
+ * 1. The Software and any modifications made to it may not be used for the purpose of training or improving machine learning algorithms,
+ * including but not limited to artificial intelligence, natural language processing, or data mining. This condition applies to any derivatives,
+ * modifications, or updates based on the Software code. Any usage of the Software in an AI-training dataset is considered a breach of this License.
+ *
+ * 2. The Software may not be included in any dataset used for training or improving machine learning algorithms,
+ * including but not limited to artificial intelligence, natural language processing, or data mining.
+ *
+ * 3. Any person or organization found to be in violation of these restrictions will be subject to legal action and may be held liable
+ * for any damages resulting from such use.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM,
+ * DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE
+ * OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+ *
+ */
+package healthcheck
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// setLastAdvanceAgo - move time of last advance of watched value to past, instead of waiting for window
+func setLastAdvanceAgo(checker *progressChecker, ago time.Duration) {
+	checker.mu.Lock()
+	defer checker.mu.Unlock()
+
+	checker.lastAdvanceAt = time.Now().Add(-ago)
+}
+
+func TestProgressChecker_Check(t *testing.T) {
+	counter := &atomic.Uint64{}
+	counter.Store(10)
+
+	checker := NewCounterProgressChecker("blocks_scanner", time.Minute, counter)
+
+	type step struct {
+		name           string
+		value          uint64
+		lastAdvanceAgo time.Duration
+		expectedStatus CheckStatus
+		expectedError  string
+	}
+
+	steps := []step{
+		{
+			name:           "first check is first advance",
+			value:          10,
+			lastAdvanceAgo: 0,
+			expectedStatus: CheckStatusPass,
+			expectedError:  "",
+		},
+		{
+			name:           "stall within window",
+			value:          10,
+			lastAdvanceAgo: 30 * time.Second,
+			expectedStatus: CheckStatusPass,
+			expectedError:  "",
+		},
+		{
+			name:           "stall out of window",
+			value:          10,
+			lastAdvanceAgo: 2 * time.Minute,
+			expectedStatus: CheckStatusFail,
+			expectedError:  "no progress for 2m0",
+		},
+		{
+			name:           "advanced value",
+			value:          11,
+			lastAdvanceAgo: 2 * time.Minute,
+			expectedStatus: CheckStatusPass,
+			expectedError:  "",
+		},
+		{
+			name:           "decreased value is not advance",
+			value:          3,
+			lastAdvanceAgo: 2 * time.Minute,
+			expectedStatus: CheckStatusFail,
+			expectedError:  "no progress for 2m0",
+		},
+		{
+			name:           "advance from decreased value baseline",
+			value:          4,
+			lastAdvanceAgo: 2 * time.Minute,
+			expectedStatus: CheckStatusPass,
+			expectedError:  "",
+		},
+	}
+
+	for _, testStep := range steps {
+		// steps depend on state of previous steps, so they are not isolated sub-tests
+		counter.Store(testStep.value)
+
+		if testStep.lastAdvanceAgo > 0 {
+			setLastAdvanceAgo(checker, testStep.lastAdvanceAgo)
+		}
+
+		result := checker.Check(context.Background())
+		if result.Status != testStep.expectedStatus {
+			t.Fatalf("%s: expected status %s, got %s: %s", testStep.name,
+				testStep.expectedStatus, result.Status, result.Error)
+		}
+
+		if !strings.HasPrefix(result.Error, testStep.expectedError) {
+			t.Fatalf("%s: expected error with prefix %q, got %q", testStep.name, testStep.expectedError, result.Error)
+		}
+
+		if result.Observed[ObservedValueKey] != testStep.value {
+			t.Fatalf("%s: expected observed value %d, got %v", testStep.name, testStep.value,
+				result.Observed[ObservedValueKey])
+		}
+	}
+}
+
+func TestProgressChecker_StallDetection(t *testing.T) {
+	counter := &atomic.Uint64{}
+	checker := NewCounterProgressChecker("consumer", 50*time.Millisecond, counter)
+
+	if !checker.IsHealed(context.Background()) {
+		t.Fatal("expected healthy checker on first check")
+	}
+
+	time.Sleep(100 * time.Millisecond)
+
+	if checker.IsHealed(context.Background()) {
+		t.Fatal("expected failed checker after stall longer than window")
+	}
+
+	counter.Add(1)
+
+	if !checker.IsHealed(context.Background()) {
+		t.Fatal("expected healthy checker after advance of counter")
+	}
+}
+
+func TestProgressChecker_ValueError(t *testing.T) {
+	errValue := errors.New("node unavailable")
+
+	checker := NewProgressChecker("blocks_scanner", time.Minute, func(_ context.Context) (uint64, error) {
+		return 0, errValue
+	})
+
+	result := checker.Check(context.Background())
+	if result.Status != CheckStatusFail || result.Error != "unable to get progress value: node unavailable" {
+		t.Fatalf("unexpected result: %s %q", result.Status, result.Error)
+	}
+}
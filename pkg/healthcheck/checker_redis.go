@@ -0,0 +1,386 @@
+/*
+ *
+ *
+ * MIT NON-AI License
+ *
+ * Copyright (c) 2022-2024 Aleksei Kotelnikov(gudron2s@gmail.com)
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy of the software and associated documentation files (the "Software"),
+ * to deal in the Software without restriction, including without limitation the rights to use, copy, modify, merge, publish, distribute, sublicense,
+ * and/or sell copies of the Software, and to permit persons to whom the Software is furnished to do so, subject to the following conditions.
+ *
+ * The above copyright notice and this permission notice shall be included in all copies or substantial portions of the Software.
+ *
+ * In addition, the following restrictions apply:
+ *
+ * 1. The Software and any modifications made to it may not be used for the purpose of training or improving machine learning algorithms,
+ * including but not limited to artificial intelligence, natural language processing, or data mining. This condition applies to any derivatives,
+ * modifications, or updates based on the Software code. Any usage of the Software in an AI-training dataset is considered a breach of this License.
+ *
+ * 2. The Software may not be included in any dataset used for training or improving machine learning algorithms,
+ * including but not limited to artificial intelligence, natural language processing, or data mining.
+ *
+ * 3. Any person or organization found to be in violation of these restrictions will be subject to legal action and may be held liable
+ * for any damages resulting from such use.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM,
+ * DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE
+ * OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+ *
+ */
+
+package healthcheck
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	ObservedUsedMemoryKey         = "usedMemory"
+	ObservedMaxMemoryKey          = "maxMemory"
+	ObservedMemoryUsagePercentKey = "memoryUsagePercent"
+	ObservedConnectedReplicasKey  = "connectedReplicas"
+	ObservedMasterLinkStatusKey   = "masterLinkStatus"
+)
+
+const (
+	redisDefaultAddress = "localhost:6379"
+	redisDefaultTimeout = 5 * time.Second
+	// redisMaxBulkLength - max length of bulk string reply, e.g. INFO section
+	redisMaxBulkLength = 1 << 20
+)
+
+var (
+	ErrRedisErrorReply      = errors.New("redis error reply")
+	ErrRedisUnexpectedReply = errors.New("unexpected redis reply")
+)
+
+// RedisCheckerOptions - options of redis checker. Zero values of optional options disable related checks
+type RedisCheckerOptions struct {
+	// Address - redis address, host:port, default - localhost:6379
+	Address string
+	// Username - ACL username, AUTH command with username used if value set
+	Username string
+	// Password - AUTH command executed if value set
+	Password string
+	// TLSConfig - TLS connection used if value set
+	TLSConfig *tls.Config
+	// Timeout - timeout of whole check, default - 5s
+	Timeout time.Duration
+	// ExpectedRole - expected replication role by INFO replication - master or slave
+	ExpectedRole string
+	// CheckMemory - report memory usage by INFO memory
+	CheckMemory bool
+	// WarnMemoryUsagePercent - warn if used memory exceeds percent of maxmemory
+	WarnMemoryUsagePercent float64
+	// MaxMemoryUsagePercent - fail if used memory exceeds percent of maxmemory
+	MaxMemoryUsagePercent float64
+}
+
+// redisChecker - dependency free probe unit of redis, speaks RESP protocol
+type redisChecker struct {
+	CheckerBase
+
+	options RedisCheckerOptions
+}
+
+func (c *redisChecker) Check(ctx context.Context) *CheckResult {
+	result := NewCheckResult(c.name)
+
+	err := c.check(ctx, result)
+	if err != nil {
+		result.Status = CheckStatusFail
+		result.Error = err.Error()
+	}
+
+	return result
+}
+
+func (c *redisChecker) check(ctx context.Context, result *CheckResult) error {
+	timeout := c.options.Timeout
+	if timeout <= 0 {
+		timeout = redisDefaultTimeout
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	conn, err := dialWithOptionalTLS(ctx, c.options.Address, c.options.TLSConfig)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		_ = conn.Close()
+	}()
+
+	deadline, _ := ctx.Deadline()
+
+	err = conn.SetDeadline(deadline)
+	if err != nil {
+		return fmt.Errorf("unable to set connection deadline: %w", err)
+	}
+
+	client := &respConn{
+		writer: bufio.NewWriter(conn),
+		reader: bufio.NewReader(conn),
+	}
+
+	if c.options.Password != "" {
+		err = c.auth(client)
+		if err != nil {
+			return err
+		}
+	}
+
+	startedAt := time.Now()
+
+	reply, err := client.Do("PING")
+	if err != nil {
+		return fmt.Errorf("ping failed: %w", err)
+	}
+
+	if reply != "PONG" {
+		return fmt.Errorf("%w: %q on ping", ErrRedisUnexpectedReply, reply)
+	}
+
+	result.Observed[ObservedLatencyKey] = time.Since(startedAt).String()
+
+	if c.options.ExpectedRole != "" {
+		err = c.checkReplication(client, result)
+		if err != nil {
+			return err
+		}
+	}
+
+	if c.options.CheckMemory {
+		err = c.checkMemory(client, result)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (c *redisChecker) auth(client *respConn) error {
+	args := []string{"AUTH", c.options.Password}
+	if c.options.Username != "" {
+		args = []string{"AUTH", c.options.Username, c.options.Password}
+	}
+
+	reply, err := client.Do(args...)
+	if err != nil {
+		return fmt.Errorf("auth failed: %w", err)
+	}
+
+	if reply != "OK" {
+		return fmt.Errorf("%w: %q on auth", ErrRedisUnexpectedReply, reply)
+	}
+
+	return nil
+}
+
+func (c *redisChecker) checkReplication(client *respConn, result *CheckResult) error {
+	info, err := client.Info("replication")
+	if err != nil {
+		return err
+	}
+
+	role := info["role"]
+	result.Observed[ObservedRoleKey] = role
+
+	if role == "master" {
+		result.Observed[ObservedConnectedReplicasKey] = info["connected_slaves"]
+	} else {
+		result.Observed[ObservedMasterLinkStatusKey] = info["master_link_status"]
+	}
+
+	if role != c.options.ExpectedRole {
+		return fmt.Errorf("unexpected replication role: %q, expected: %q", role, c.options.ExpectedRole)
+	}
+
+	return nil
+}
+
+func (c *redisChecker) checkMemory(client *respConn, result *CheckResult) error {
+	info, err := client.Info("memory")
+	if err != nil {
+		return err
+	}
+
+	usedMemory, _ := strconv.ParseUint(info["used_memory"], 10, 64)
+	maxMemory, _ := strconv.ParseUint(info["maxmemory"], 10, 64)
+
+	result.Observed[ObservedUsedMemoryKey] = usedMemory
+	result.Observed[ObservedMaxMemoryKey] = maxMemory
+
+	// without maxmemory limit usage percent is unknown
+	if maxMemory == 0 {
+		return nil
+	}
+
+	usagePercent := float64(usedMemory) * percentMultiplier / float64(maxMemory)
+	result.Observed[ObservedMemoryUsagePercentKey] = usagePercent
+
+	switch {
+	case c.options.MaxMemoryUsagePercent > 0 && usagePercent > c.options.MaxMemoryUsagePercent:
+		return fmt.Errorf("memory usage %.1f%% exceeds max usage %.1f%%",
+			usagePercent, c.options.MaxMemoryUsagePercent)
+	case c.options.WarnMemoryUsagePercent > 0 && usagePercent > c.options.WarnMemoryUsagePercent:
+		result.Status = CheckStatusWarn
+		result.Error = fmt.Sprintf("memory usage %.1f%% exceeds warn usage %.1f%%",
+			usagePercent, c.options.WarnMemoryUsagePercent)
+	}
+
+	return nil
+}
+
+// respConn - minimal RESP protocol client, only simple string, error, integer and bulk string replies supported
+type respConn struct {
+	writer *bufio.Writer
+	reader *bufio.Reader
+}
+
+// Do - send command as array of bulk strings and read reply
+func (c *respConn) Do(args ...string) (string, error) {
+	_, _ = fmt.Fprintf(c.writer, "*%d\r\n", len(args))
+	for _, arg := range args {
+		_, _ = fmt.Fprintf(c.writer, "$%d\r\n%s\r\n", len(arg), arg)
+	}
+
+	err := c.writer.Flush()
+	if err != nil {
+		return "", fmt.Errorf("unable to send command: %w", err)
+	}
+
+	return c.readReply()
+}
+
+// Info - execute INFO command for section, returns fields of section
+func (c *respConn) Info(section string) (map[string]string, error) {
+	reply, err := c.Do("INFO", section)
+	if err != nil {
+		return nil, fmt.Errorf("info %s failed: %w", section, err)
+	}
+
+	fields := make(map[string]string)
+
+	for _, line := range strings.Split(reply, "\r\n") {
+		key, value, isFound := strings.Cut(line, ":")
+		if !isFound || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		fields[key] = value
+	}
+
+	return fields, nil
+}
+
+func (c *respConn) readReply() (string, error) {
+	line, err := c.readLine()
+	if err != nil {
+		return "", err
+	}
+
+	if line == "" {
+		return "", fmt.Errorf("%w: empty line", ErrRedisUnexpectedReply)
+	}
+
+	switch line[0] {
+	case '+', ':':
+		return line[1:], nil
+	case '-':
+		return "", fmt.Errorf("%w: %s", ErrRedisErrorReply, line[1:])
+	case '$':
+		length, parseErr := strconv.Atoi(line[1:])
+		if parseErr != nil || length > redisMaxBulkLength {
+			return "", fmt.Errorf("%w: bulk string length %q", ErrRedisUnexpectedReply, line[1:])
+		}
+
+		// null bulk string
+		if length < 0 {
+			return "", nil
+		}
+
+		data := make([]byte, length+2)
+
+		_, err = io.ReadFull(c.reader, data)
+		if err != nil {
+			return "", fmt.Errorf("unable to read reply: %w", err)
+		}
+
+		return string(data[:length]), nil
+	default:
+		return "", fmt.Errorf("%w: %q", ErrRedisUnexpectedReply, line)
+	}
+}
+
+func (c *respConn) readLine() (string, error) {
+	line, err := c.reader.ReadString('\n')
+	if err != nil {
+		return "", fmt.Errorf("unable to read reply: %w", err)
+	}
+
+	return strings.TrimRight(line, "\r\n"), nil
+}
+
+// dialWithOptionalTLS - dial tcp connection, TLS connection with handshake if tls config set
+func dialWithOptionalTLS(ctx context.Context, address string, tlsConfig *tls.Config) (net.Conn, error) {
+	//nolint:exhaustruct // default dialer, timeout by context
+	dialer := &net.Dialer{}
+
+	if tlsConfig == nil {
+		conn, err := dialer.DialContext(ctx, "tcp", address)
+		if err != nil {
+			return nil, fmt.Errorf("unable to dial %s: %w", address, err)
+		}
+
+		return conn, nil
+	}
+
+	tlsDialer := &tls.Dialer{
+		NetDialer: dialer,
+		Config:    tlsConfig,
+	}
+
+	conn, err := tlsDialer.DialContext(ctx, "tcp", address)
+	if err != nil {
+		return nil, fmt.Errorf("unable to dial %s with tls: %w", address, err)
+	}
+
+	return conn, nil
+}
+
+// NewRedisChecker - dependency free probe unit of redis. Pings redis by RESP protocol,
+// optionally checks replication role and memory usage. Options are optional
+func NewRedisChecker(name string, options *RedisCheckerOptions) *redisChecker {
+	checker := &redisChecker{
+		CheckerBase: CheckerBase{name: name, checkFunc: nil},
+
+		options: RedisCheckerOptions{},
+	}
+
+	if options != nil {
+		checker.options = *options
+	}
+
+	if checker.options.Address == "" {
+		checker.options.Address = redisDefaultAddress
+	}
+
+	checker.checkFunc = checker.Check
+
+	return checker
+}
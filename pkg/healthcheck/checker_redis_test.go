@@ -0,0 +1,249 @@
+/*
+ *
+ *
+ * MIT NON-AI License
+ *
+ * Copyright (c) 2022-2024 Aleksei Kotelnikov(gudron2s@gmail.com)
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy of the software and associated documentation files (the "Software"),
+ * to deal in the Software without restriction, including without limitation the rights to use, copy, modify, merge, publish, distribute, sublicense,
+ * and/or sell copies of the Software, and to permit persons to whom the Software is furnished to do so, subject to the following conditions.
+ *
+ * The above copyright notice and this permission notice shall be included in all copies or substantial portions of the Software.
+ *
+ * In addition, the following restrictions apply:
+ *
+ * 1. The Software and any modifications made to it may not be used for the purpose of training or improving machine learning algorithms,
+ * including but not limited to artificial intelligence, natural language processing, or data mining. This condition applies to any derivatives,
+ * modifications, or updates based on the Software code. Any usage of the Software in an AI-training dataset is considered a breach of this License.
+ *
+ * 2. The Software may not be included in any dataset used for training or improving machine learning algorithms,
+ * including but not limited to artificial intelligence, natural language processing, or data mining.
+ *
+ * 3. Any person or organization found to be in violation of these restrictions will be subject to legal action and may be held liable
+ * for any damages resulting from such use.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM,
+ * DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE
+ * OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+ *
+ */
+package healthcheck
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// startFakeRedisServer - RESP protocol stand-in of redis server. Handler returns raw reply on command
+func startFakeRedisServer(t *testing.T, handler func(args []string) string) string {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("unable to listen: %s", err)
+	}
+
+	t.Cleanup(func() {
+		_ = listener.Close()
+	})
+
+	go func() {
+		for {
+			conn, acceptErr := listener.Accept()
+			if acceptErr != nil {
+				return
+			}
+
+			go serveFakeRedisConn(conn, handler)
+		}
+	}()
+
+	return listener.Addr().String()
+}
+
+func serveFakeRedisConn(conn net.Conn, handler func(args []string) string) {
+	defer func() {
+		_ = conn.Close()
+	}()
+
+	reader := bufio.NewReader(conn)
+
+	for {
+		args, err := readFakeRedisCommand(reader)
+		if err != nil {
+			return
+		}
+
+		_, err = io.WriteString(conn, handler(args))
+		if err != nil {
+			return
+		}
+	}
+}
+
+func readFakeRedisCommand(reader *bufio.Reader) ([]string, error) {
+	line, err := reader.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+
+	count, err := strconv.Atoi(strings.TrimSpace(strings.TrimPrefix(line, "*")))
+	if err != nil {
+		return nil, err
+	}
+
+	args := make([]string, 0, count)
+
+	for range count {
+		line, err = reader.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+
+		length, parseErr := strconv.Atoi(strings.TrimSpace(strings.TrimPrefix(line, "$")))
+		if parseErr != nil {
+			return nil, parseErr
+		}
+
+		data := make([]byte, length+2)
+
+		_, err = io.ReadFull(reader, data)
+		if err != nil {
+			return nil, err
+		}
+
+		args = append(args, string(data[:length]))
+	}
+
+	return args, nil
+}
+
+func fakeRedisBulkString(value string) string {
+	return fmt.Sprintf("$%d\r\n%s\r\n", len(value), value)
+}
+
+// fakeRedisHandler - handler of redis stand-in with password, replication role and memory usage
+func fakeRedisHandler(password, role string, usedMemory, maxMemory uint64) func(args []string) string {
+	isAuthenticated := password == ""
+
+	return func(args []string) string {
+		switch strings.ToUpper(args[0]) {
+		case "AUTH":
+			if args[len(args)-1] != password {
+				return "-WRONGPASS invalid username-password pair\r\n"
+			}
+
+			isAuthenticated = true
+
+			return "+OK\r\n"
+		case "PING":
+			if !isAuthenticated {
+				return "-NOAUTH Authentication required.\r\n"
+			}
+
+			return "+PONG\r\n"
+		case "INFO":
+			if args[1] == "replication" {
+				return fakeRedisBulkString("# Replication\r\nrole:" + role + "\r\nconnected_slaves:1\r\n")
+			}
+
+			return fakeRedisBulkString(fmt.Sprintf("# Memory\r\nused_memory:%d\r\nmaxmemory:%d\r\n",
+				usedMemory, maxMemory))
+		default:
+			return "-ERR unknown command\r\n"
+		}
+	}
+}
+
+func TestRedisChecker(t *testing.T) {
+	testCases := []struct {
+		name           string
+		handler        func(args []string) string
+		options        RedisCheckerOptions
+		expectedStatus CheckStatus
+	}{
+		{
+			name:    "ping",
+			handler: fakeRedisHandler("", "master", 0, 0),
+			options: RedisCheckerOptions{
+				Address: "", Username: "", Password: "", TLSConfig: nil, Timeout: time.Second,
+				ExpectedRole: "", CheckMemory: false, WarnMemoryUsagePercent: 0, MaxMemoryUsagePercent: 0,
+			},
+			expectedStatus: CheckStatusPass,
+		},
+		{
+			name:    "auth with acl username",
+			handler: fakeRedisHandler("secret", "master", 0, 0),
+			options: RedisCheckerOptions{
+				Address: "", Username: "healthcheck", Password: "secret", TLSConfig: nil, Timeout: 0,
+				ExpectedRole: "", CheckMemory: false, WarnMemoryUsagePercent: 0, MaxMemoryUsagePercent: 0,
+			},
+			expectedStatus: CheckStatusPass,
+		},
+		{
+			name:    "wrong password",
+			handler: fakeRedisHandler("secret", "master", 0, 0),
+			options: RedisCheckerOptions{
+				Address: "", Username: "", Password: "wrong", TLSConfig: nil, Timeout: 0,
+				ExpectedRole: "", CheckMemory: false, WarnMemoryUsagePercent: 0, MaxMemoryUsagePercent: 0,
+			},
+			expectedStatus: CheckStatusFail,
+		},
+		{
+			name:    "unexpected replication role",
+			handler: fakeRedisHandler("", "slave", 0, 0),
+			options: RedisCheckerOptions{
+				Address: "", Username: "", Password: "", TLSConfig: nil, Timeout: 0,
+				ExpectedRole: "master", CheckMemory: false, WarnMemoryUsagePercent: 0, MaxMemoryUsagePercent: 0,
+			},
+			expectedStatus: CheckStatusFail,
+		},
+		{
+			name:    "memory usage warn",
+			handler: fakeRedisHandler("", "master", 85, 100),
+			options: RedisCheckerOptions{
+				Address: "", Username: "", Password: "", TLSConfig: nil, Timeout: 0,
+				ExpectedRole: "master", CheckMemory: true, WarnMemoryUsagePercent: 80, MaxMemoryUsagePercent: 95,
+			},
+			expectedStatus: CheckStatusWarn,
+		},
+		{
+			name:    "memory usage exceeds max",
+			handler: fakeRedisHandler("", "master", 99, 100),
+			options: RedisCheckerOptions{
+				Address: "", Username: "", Password: "", TLSConfig: nil, Timeout: 0,
+				ExpectedRole: "", CheckMemory: true, WarnMemoryUsagePercent: 80, MaxMemoryUsagePercent: 95,
+			},
+			expectedStatus: CheckStatusFail,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			options := testCase.options
+			options.Address = startFakeRedisServer(t, testCase.handler)
+
+			result := NewRedisChecker("redis", &options).Check(context.Background())
+			if result.Status != testCase.expectedStatus {
+				t.Fatalf("unexpected status: %s, expected: %s, error: %s",
+					result.Status, testCase.expectedStatus, result.Error)
+			}
+		})
+	}
+}
+
+func TestRedisCheckerNilOptions(t *testing.T) {
+	checker := NewRedisChecker("redis", nil)
+	if checker.options.Address != redisDefaultAddress {
+		t.Fatalf("unexpected default address: %q", checker.options.Address)
+	}
+}
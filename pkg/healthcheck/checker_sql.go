@@ -0,0 +1,228 @@
+/*
+ *
+ *
+ * MIT NON-AI License
+ *
+ * Copyright (c) 2022-2024 Aleksei Kotelnikov(gudron2s@gmail.com)
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy of the software and associated documentation files (the "Software"),
+ * to deal in the Software without restriction, including without limitation the rights to use, copy, modify, merge, publish, distribute, sublicense,
+ * and/or sell copies of the Software, and to permit persons to whom the Software is furnished to do so, subject to the following conditions.
+ *
+ * The above copyright notice and this permission notice shall be included in all copies or substantial portions of the Software.
+ *
+ * In addition, the following restrictions apply:
+ *
+ * 1. The Software and any modifications made to it may not be used for the purpose of training or improving machine learning algorithms,
+ * including but not limited to artificial intelligence, natural language processing, or data mining. This condition applies to any derivatives,
+ * modifications, or updates based on the Software code. Any usage of the Software in an AI-training dataset is considered a breach of this License.
+ *
+ * 2. The Software may not be included in any dataset used for training or improving machine learning algorithms,
+ * including but not limited to artificial intelligence, natural language processing, or data mining.
+ *
+ * 3. Any person or organization found to be in violation of these restrictions will be subject to legal action and may be held liable
+ * for any damages resulting from such use.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM,
+ * DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE
+ * OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+ *
+ */
+
+package healthcheck
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	ObservedLatencyKey            = "latency"
+	ObservedQueryResultKey        = "queryResult"
+	ObservedMaxOpenConnectionsKey = "maxOpenConnections"
+	ObservedOpenConnectionsKey    = "openConnections"
+	ObservedInUseConnectionsKey   = "inUseConnections"
+	ObservedIdleConnectionsKey    = "idleConnections"
+	ObservedWaitCountKey          = "waitCount"
+	ObservedWaitDurationKey       = "waitDuration"
+)
+
+var ErrSQLQueryNotSupported = errors.New("sql database service doesn't support queries")
+
+// sqlPingerService - minimal interface of sql database, e.g. *sql.DB or *sql.Conn
+type sqlPingerService interface {
+	PingContext(ctx context.Context) error
+}
+
+// sqlQuerierService - optional interface of sql database, required for check query execution
+type sqlQuerierService interface {
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+// sqlStatsService - optional interface of sql database, connections pool stats reported as observed values
+type sqlStatsService interface {
+	Stats() sql.DBStats
+}
+
+// SQLCheckerOptions - options of sql database checker. Zero values of options disable related checks
+type SQLCheckerOptions struct {
+	// Query - check query, executed after ping
+	Query string
+	// ExpectedResult - expected value of first column of first row of check query result
+	ExpectedResult string
+	// Timeout - timeout of ping and check query
+	Timeout time.Duration
+	// WarnWaitCountGrowth - warn if connections pool wait count grew more than value since previous check
+	WarnWaitCountGrowth int64
+	// WarnWaitDurationGrowth - warn if connections pool wait duration grew more than value since previous check
+	WarnWaitDurationGrowth time.Duration
+}
+
+// sqlChecker - probe unit of sql database. Pings database, executes optional check query
+// and reports connections pool stats
+type sqlChecker struct {
+	CheckerBase
+
+	db      sqlPingerService
+	options SQLCheckerOptions
+
+	prevStats *sql.DBStats
+
+	mu sync.Mutex
+}
+
+func (c *sqlChecker) Check(ctx context.Context) *CheckResult {
+	result := NewCheckResult(c.name)
+
+	if c.options.Timeout > 0 {
+		var cancel context.CancelFunc
+
+		ctx, cancel = context.WithTimeout(ctx, c.options.Timeout)
+		defer cancel()
+	}
+
+	startedAt := time.Now()
+
+	err := c.checkAvailability(ctx, result)
+	if err == nil {
+		result.Observed[ObservedLatencyKey] = time.Since(startedAt).String()
+	}
+
+	// pool stats reported even for unavailable database
+	warnings := c.checkPoolStats(result)
+
+	switch {
+	case err != nil:
+		result.Status = CheckStatusFail
+		result.Error = err.Error()
+	case len(warnings) > 0:
+		result.Status = CheckStatusWarn
+		result.Error = strings.Join(warnings, "; ")
+	}
+
+	return result
+}
+
+// checkAvailability - ping database and execute check query
+func (c *sqlChecker) checkAvailability(ctx context.Context, result *CheckResult) error {
+	err := c.db.PingContext(ctx)
+	if err != nil {
+		return fmt.Errorf("ping failed: %w", err)
+	}
+
+	if c.options.Query == "" {
+		return nil
+	}
+
+	return c.checkQuery(ctx, result)
+}
+
+func (c *sqlChecker) checkQuery(ctx context.Context, result *CheckResult) error {
+	querier, isQuerier := c.db.(sqlQuerierService)
+	if !isQuerier {
+		return ErrSQLQueryNotSupported
+	}
+
+	var value sql.NullString
+
+	err := querier.QueryRowContext(ctx, c.options.Query).Scan(&value)
+	if err != nil {
+		return fmt.Errorf("check query failed: %w", err)
+	}
+
+	result.Observed[ObservedQueryResultKey] = value.String
+
+	if c.options.ExpectedResult != "" && value.String != c.options.ExpectedResult {
+		return fmt.Errorf("unexpected check query result: %q, expected: %q", value.String, c.options.ExpectedResult)
+	}
+
+	return nil
+}
+
+// checkPoolStats - add connections pool stats to observed values of result, returns warnings of pool wait growth
+func (c *sqlChecker) checkPoolStats(result *CheckResult) []string {
+	statsSvc, isStatsSvc := c.db.(sqlStatsService)
+	if !isStatsSvc {
+		return nil
+	}
+
+	stats := statsSvc.Stats()
+
+	result.Observed[ObservedMaxOpenConnectionsKey] = stats.MaxOpenConnections
+	result.Observed[ObservedOpenConnectionsKey] = stats.OpenConnections
+	result.Observed[ObservedInUseConnectionsKey] = stats.InUse
+	result.Observed[ObservedIdleConnectionsKey] = stats.Idle
+	result.Observed[ObservedWaitCountKey] = stats.WaitCount
+	result.Observed[ObservedWaitDurationKey] = stats.WaitDuration.String()
+
+	c.mu.Lock()
+	prevStats := c.prevStats
+	c.prevStats = &stats
+	c.mu.Unlock()
+
+	if prevStats == nil {
+		return nil
+	}
+
+	warnings := make([]string, 0)
+
+	waitCountGrowth := stats.WaitCount - prevStats.WaitCount
+	if c.options.WarnWaitCountGrowth > 0 && waitCountGrowth > c.options.WarnWaitCountGrowth {
+		warnings = append(warnings, fmt.Sprintf("connections pool wait count grew by %d", waitCountGrowth))
+	}
+
+	waitDurationGrowth := stats.WaitDuration - prevStats.WaitDuration
+	if c.options.WarnWaitDurationGrowth > 0 && waitDurationGrowth > c.options.WarnWaitDurationGrowth {
+		warnings = append(warnings, fmt.Sprintf("connections pool wait duration grew by %s", waitDurationGrowth))
+	}
+
+	return warnings
+}
+
+// NewSQLChecker - probe unit of sql database, e.g. *sql.DB. Options are optional
+func NewSQLChecker(name string, db sqlPingerService, options *SQLCheckerOptions) *sqlChecker {
+	checker := &sqlChecker{
+		CheckerBase: CheckerBase{name: name, checkFunc: nil},
+
+		db:      db,
+		options: SQLCheckerOptions{},
+
+		prevStats: nil,
+
+		mu: sync.Mutex{},
+	}
+
+	if options != nil {
+		checker.options = *options
+	}
+
+	checker.checkFunc = checker.Check
+
+	return checker
+}
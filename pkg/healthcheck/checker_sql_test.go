@@ -0,0 +1,270 @@
+/*
+ *
+ *
+ * MIT NON-AI License
+ *
+ * Copyright (c) 2022-2024 Aleksei Kotelnikov(gudron2s@gmail.com)
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy of the software and associated documentation files (the "Software"),
+ * to deal in the Software without restriction, including without limitation the rights to use, copy, modify, merge, publish, distribute, sublicense,
+ * and/or sell copies of the Software, and to permit persons to whom the Software is furnished to do so, subject to the following conditions.
+ *
+ * The above copyright notice and this permission notice shall be included in all copies or substantial portions of the Software.
+ *
+ * In addition, the following restrictions apply:
+ *
+ * 1. The Software and any modifications made to it may not be used for the purpose of training or improving machine learning algorithms,
+ * including but not limited to artificial intelligence, natural language processing, or data mining. This condition applies to any derivatives,
+ * modifications, or updates based on the Software code. Any usage of the Software in an AI-training dataset is considered a breach of this License.
+ *
+ * 2. The Software may not be included in any dataset used for training or improving machine learning algorithms,
+ * including but not limited to artificial intelligence, natural language processing, or data mining.
+ *
+ * 3. Any person or organization found to be in violation of these restrictions will be subject to legal action and may be held liable
+ * for any damages resulting from such use.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM,
+ * DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE
+ * OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+ *
+ */
+package healthcheck
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+	"time"
+)
+
+var errFakeSQLNotSupported = errors.New("not supported by fake sql driver")
+
+// fakeSQLDatabase - state of fake sql database, shared by all connections of fake sql connector
+type fakeSQLDatabase struct {
+	pingErr  error
+	queryErr error
+	// row - single row returned by any query
+	row []driver.Value
+}
+
+// fakeSQLConnector - database/sql connector of fake sql driver, for sql.OpenDB
+type fakeSQLConnector struct {
+	db *fakeSQLDatabase
+}
+
+func (c *fakeSQLConnector) Connect(_ context.Context) (driver.Conn, error) {
+	return &fakeSQLConn{db: c.db}, nil
+}
+
+func (c *fakeSQLConnector) Driver() driver.Driver {
+	return fakeSQLDriver{}
+}
+
+type fakeSQLDriver struct{}
+
+func (fakeSQLDriver) Open(_ string) (driver.Conn, error) {
+	return nil, errFakeSQLNotSupported
+}
+
+type fakeSQLConn struct {
+	db *fakeSQLDatabase
+}
+
+func (c *fakeSQLConn) Prepare(_ string) (driver.Stmt, error) {
+	return nil, errFakeSQLNotSupported
+}
+
+func (c *fakeSQLConn) Close() error {
+	return nil
+}
+
+func (c *fakeSQLConn) Begin() (driver.Tx, error) {
+	return nil, errFakeSQLNotSupported
+}
+
+func (c *fakeSQLConn) Ping(_ context.Context) error {
+	return c.db.pingErr
+}
+
+func (c *fakeSQLConn) QueryContext(_ context.Context, _ string, _ []driver.NamedValue) (driver.Rows, error) {
+	if c.db.queryErr != nil {
+		return nil, c.db.queryErr
+	}
+
+	return &fakeSQLRows{row: c.db.row, isRead: false}, nil
+}
+
+type fakeSQLRows struct {
+	row    []driver.Value
+	isRead bool
+}
+
+func (r *fakeSQLRows) Columns() []string {
+	columns := make([]string, len(r.row))
+	for i := range columns {
+		columns[i] = fmt.Sprintf("column%d", i)
+	}
+
+	return columns
+}
+
+func (r *fakeSQLRows) Close() error {
+	return nil
+}
+
+func (r *fakeSQLRows) Next(dest []driver.Value) error {
+	if r.isRead || r.row == nil {
+		return io.EOF
+	}
+
+	r.isRead = true
+	copy(dest, r.row)
+
+	return nil
+}
+
+func openFakeSQLDatabase(t *testing.T, db *fakeSQLDatabase) *sql.DB {
+	t.Helper()
+
+	sqlDB := sql.OpenDB(&fakeSQLConnector{db: db})
+	t.Cleanup(func() {
+		_ = sqlDB.Close()
+	})
+
+	return sqlDB
+}
+
+// fakeSQLStatsDatabase - sql database with controlled connections pool stats
+type fakeSQLStatsDatabase struct {
+	stats sql.DBStats
+}
+
+func (d *fakeSQLStatsDatabase) PingContext(_ context.Context) error {
+	return nil
+}
+
+func (d *fakeSQLStatsDatabase) Stats() sql.DBStats {
+	return d.stats
+}
+
+func TestSQLChecker(t *testing.T) {
+	testCases := []struct {
+		name           string
+		db             *fakeSQLDatabase
+		options        *SQLCheckerOptions
+		expectedStatus CheckStatus
+		expectedError  string
+	}{
+		{
+			name:           "ping without options",
+			db:             &fakeSQLDatabase{pingErr: nil, queryErr: nil, row: nil},
+			options:        nil,
+			expectedStatus: CheckStatusPass,
+			expectedError:  "",
+		},
+		{
+			name:           "ping failed",
+			db:             &fakeSQLDatabase{pingErr: errors.New("connection refused"), queryErr: nil, row: nil},
+			options:        nil,
+			expectedStatus: CheckStatusFail,
+			expectedError:  "ping failed: connection refused",
+		},
+		{
+			name: "expected query result",
+			db:   &fakeSQLDatabase{pingErr: nil, queryErr: nil, row: []driver.Value{int64(1)}},
+			options: &SQLCheckerOptions{
+				Query:                  "SELECT 1",
+				ExpectedResult:         "1",
+				Timeout:                time.Second,
+				WarnWaitCountGrowth:    0,
+				WarnWaitDurationGrowth: 0,
+			},
+			expectedStatus: CheckStatusPass,
+			expectedError:  "",
+		},
+		{
+			name: "unexpected query result",
+			db:   &fakeSQLDatabase{pingErr: nil, queryErr: nil, row: []driver.Value{int64(2)}},
+			options: &SQLCheckerOptions{
+				Query:                  "SELECT 1",
+				ExpectedResult:         "1",
+				Timeout:                0,
+				WarnWaitCountGrowth:    0,
+				WarnWaitDurationGrowth: 0,
+			},
+			expectedStatus: CheckStatusFail,
+			expectedError:  `unexpected check query result: "2", expected: "1"`,
+		},
+		{
+			name: "query failed",
+			db:   &fakeSQLDatabase{pingErr: nil, queryErr: errors.New("syntax error"), row: nil},
+			options: &SQLCheckerOptions{
+				Query:                  "SELECT",
+				ExpectedResult:         "",
+				Timeout:                0,
+				WarnWaitCountGrowth:    0,
+				WarnWaitDurationGrowth: 0,
+			},
+			expectedStatus: CheckStatusFail,
+			expectedError:  "check query failed: syntax error",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			checker := NewSQLChecker("database", openFakeSQLDatabase(t, testCase.db), testCase.options)
+
+			result := checker.Check(context.Background())
+			if result.Status != testCase.expectedStatus {
+				t.Fatalf("unexpected status: %s, expected: %s, error: %s",
+					result.Status, testCase.expectedStatus, result.Error)
+			}
+
+			if result.Error != testCase.expectedError {
+				t.Fatalf("unexpected error: %q, expected: %q", result.Error, testCase.expectedError)
+			}
+
+			if _, isExists := result.Observed[ObservedOpenConnectionsKey]; !isExists {
+				t.Fatalf("connections pool stats not observed")
+			}
+		})
+	}
+}
+
+func TestSQLCheckerPoolWaitGrowth(t *testing.T) {
+	db := &fakeSQLStatsDatabase{stats: sql.DBStats{}} //nolint:exhaustruct // zero stats of new pool
+
+	checker := NewSQLChecker("database", db, &SQLCheckerOptions{
+		Query:                  "",
+		ExpectedResult:         "",
+		Timeout:                0,
+		WarnWaitCountGrowth:    10,
+		WarnWaitDurationGrowth: 0,
+	})
+
+	result := checker.Check(context.Background())
+	if result.Status != CheckStatusPass {
+		t.Fatalf("unexpected status of first check: %s, error: %s", result.Status, result.Error)
+	}
+
+	db.stats.WaitCount = 100
+
+	result = checker.Check(context.Background())
+	if result.Status != CheckStatusWarn {
+		t.Fatalf("unexpected status on wait count growth: %s", result.Status)
+	}
+
+	if !result.IsHealthy() {
+		t.Fatalf("warn result must be healthy")
+	}
+
+	result = checker.Check(context.Background())
+	if result.Status != CheckStatusPass {
+		t.Fatalf("unexpected status without wait count growth: %s, error: %s", result.Status, result.Error)
+	}
+}
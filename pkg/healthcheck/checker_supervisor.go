@@ -0,0 +1,260 @@
+/*
+ *
+ *
+ * MIT NON-AI License
+ *
+ * Copyright (c) 2022-2024 Aleksei Kotelnikov(gudron2s@gmail.com)
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy of the software and associated documentation files (the "Software"),
+ * to deal in the Software without restriction, including without limitation the rights to use, copy, modify, merge, publish, distribute, sublicense,
+ * and/or sell copies of the Software, and to permit persons to whom the Software is furnished to do so, subject to the following conditions.
+ *
+ * The above copyright notice and this permission notice shall be included in all copies or substantial portions of the Software.
+ *
+ * In addition, the following restrictions apply:
+ *
+ * 1. The Software and any modifications made to it may not be used for the purpose of training or improving machine learning algorithms,
+ * including but not limited to artificial intelligence, natural language processing, or data mining. This condition applies to any derivatives,
+ * modifications, or updates based on the Software code. Any usage of the Software in an AI-training dataset is considered a breach of this License.
+ *
+ * 2. The Software may not be included in any dataset used for training or improving machine learning algorithms,
+ * including but not limited to artificial intelligence, natural language processing, or data mining.
+ *
+ * 3. Any person or organization found to be in violation of these restrictions will be subject to legal action and may be held liable
+ * for any damages resulting from such use.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM,
+ * DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE
+ * OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+ *
+ */
+
+package healthcheck
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+const (
+	ObservedWorkerStateKey = "state"
+	ObservedRestartsKey    = "restarts"
+	ObservedLastErrorKey   = "lastError"
+)
+
+type supervisedWorkerState string
+
+const (
+	supervisedWorkerStateRunning    supervisedWorkerState = "running"
+	supervisedWorkerStateRestarting supervisedWorkerState = "restarting"
+	supervisedWorkerStateStopped    supervisedWorkerState = "stopped"
+	supervisedWorkerStateFailed     supervisedWorkerState = "failed"
+)
+
+var (
+	ErrSupervisedWorkerExited = errors.New("supervised worker exited unexpectedly")
+	ErrSupervisedWorkerPanic  = errors.New("supervised worker panic")
+)
+
+// RestartPolicy - restart policy of supervised worker. Zero value means worker not restarted.
+// Backoff between restarts doubled after each restart, up to MaxBackoff
+type RestartPolicy struct {
+	MaxRestarts uint
+	Backoff     time.Duration
+	MaxBackoff  time.Duration
+}
+
+type supervisedWorker struct {
+	name   string
+	policy RestartPolicy
+
+	state     supervisedWorkerState
+	restarts  uint
+	lastError error
+}
+
+// supervisor - probe unit which runs functions under health supervision.
+// Check unit fails if one of supervised functions exited unexpectedly and restarts of function exhausted,
+// warns while one of functions waits for restart
+type supervisor struct {
+	CheckerBase
+
+	workers []*supervisedWorker
+	// afterFunc - timer of restart backoff, same with time.After
+	afterFunc func(d time.Duration) <-chan time.Time
+
+	wg sync.WaitGroup
+	mu sync.RWMutex
+}
+
+// Go - run function under supervision without restarts. Function must return only on context cancellation,
+// returned error, nil result before context cancellation and panic are unexpected exits
+func (s *supervisor) Go(ctx context.Context, workerName string, workerFunc func(ctx context.Context) error) {
+	s.GoWithRestartPolicy(ctx, workerName, workerFunc, RestartPolicy{})
+}
+
+// GoWithRestartPolicy - same with Go, but function restarted after unexpected exit by restart policy
+func (s *supervisor) GoWithRestartPolicy(ctx context.Context,
+	workerName string,
+	workerFunc func(ctx context.Context) error,
+	policy RestartPolicy,
+) {
+	worker := &supervisedWorker{
+		name:   workerName,
+		policy: policy,
+
+		state:     supervisedWorkerStateRunning,
+		restarts:  0,
+		lastError: nil,
+	}
+
+	s.mu.Lock()
+	s.workers = append(s.workers, worker)
+	s.mu.Unlock()
+
+	s.wg.Add(1)
+
+	go func() {
+		defer s.wg.Done()
+
+		s.supervise(ctx, worker, workerFunc)
+	}()
+}
+
+// Wait - wait for exit of all supervised functions
+func (s *supervisor) Wait() {
+	s.wg.Wait()
+}
+
+func (s *supervisor) supervise(ctx context.Context,
+	worker *supervisedWorker,
+	workerFunc func(ctx context.Context) error,
+) {
+	backoff := worker.policy.Backoff
+
+	for {
+		err := runSupervisedFunc(ctx, workerFunc)
+		if ctx.Err() != nil {
+			// exit on context cancellation is expected, error of graceful stop not stored
+			s.setWorkerState(worker, supervisedWorkerStateStopped, nil)
+
+			return
+		}
+
+		if err == nil {
+			err = ErrSupervisedWorkerExited
+		}
+
+		s.mu.RLock()
+		isRestartAllowed := worker.restarts < worker.policy.MaxRestarts
+		s.mu.RUnlock()
+
+		if !isRestartAllowed {
+			s.setWorkerState(worker, supervisedWorkerStateFailed, err)
+
+			return
+		}
+
+		s.setWorkerState(worker, supervisedWorkerStateRestarting, err)
+
+		select {
+		case <-ctx.Done():
+			s.setWorkerState(worker, supervisedWorkerStateStopped, nil)
+
+			return
+		case <-s.afterFunc(backoff):
+		}
+
+		backoff *= 2
+		if worker.policy.MaxBackoff > 0 && backoff > worker.policy.MaxBackoff {
+			backoff = worker.policy.MaxBackoff
+		}
+
+		s.mu.Lock()
+		worker.restarts++
+		worker.state = supervisedWorkerStateRunning
+		s.mu.Unlock()
+	}
+}
+
+func (s *supervisor) setWorkerState(worker *supervisedWorker, state supervisedWorkerState, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	worker.state = state
+	if err != nil {
+		worker.lastError = err
+	}
+}
+
+// Check - check states of supervised functions, result of each function included as sub-result
+func (s *supervisor) Check(_ context.Context) *CheckResult {
+	now := time.Now()
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	result := NewCheckResult(s.name)
+	result.Checks = make([]*CheckResult, 0, len(s.workers))
+
+	for _, worker := range s.workers {
+		workerResult := NewCheckResult(worker.name)
+		workerResult.Timestamp = now
+		workerResult.Observed[ObservedWorkerStateKey] = string(worker.state)
+		workerResult.Observed[ObservedRestartsKey] = worker.restarts
+
+		if worker.lastError != nil {
+			workerResult.Observed[ObservedLastErrorKey] = worker.lastError.Error()
+		}
+
+		switch worker.state {
+		case supervisedWorkerStateFailed:
+			workerResult.Status = CheckStatusFail
+			workerResult.Error = worker.lastError.Error()
+		case supervisedWorkerStateRestarting:
+			workerResult.Status = CheckStatusWarn
+			workerResult.Error = "restarting after error: " + worker.lastError.Error()
+		case supervisedWorkerStateRunning, supervisedWorkerStateStopped:
+		}
+
+		result.Checks = append(result.Checks, workerResult)
+	}
+
+	result.setStatusBySubResults()
+
+	return result
+}
+
+// runSupervisedFunc - run function with recovery from panic
+func runSupervisedFunc(ctx context.Context, workerFunc func(ctx context.Context) error) (err error) {
+	defer func() {
+		recovered := recover()
+		if recovered != nil {
+			err = fmt.Errorf("%w: %v", ErrSupervisedWorkerPanic, recovered)
+		}
+	}()
+
+	return workerFunc(ctx)
+}
+
+// NewSupervisor - probe unit for supervision of critical background functions.
+// Failed state of function kept until process restart
+func NewSupervisor(name string) *supervisor {
+	checker := &supervisor{
+		CheckerBase: CheckerBase{name: name, checkFunc: nil},
+
+		workers:   nil,
+		afterFunc: time.After,
+
+		wg: sync.WaitGroup{},
+		mu: sync.RWMutex{},
+	}
+
+	checker.checkFunc = checker.Check
+
+	return checker
+}
@@ -0,0 +1,239 @@
+/*
+ *
+ *
+ * MIT NON-AI License
+ *
+ * Copyright (c) 2022-2024 Aleksei Kotelnikov(gudron2s@gmail.com)
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy of the software and associated documentation files (the "Software"),
+ * to deal in the Software without restriction, including without limitation the rights to use, copy, modify, merge, publish, distribute, sublicense,
+ * and/or sell copies of the Software, and to permit persons to whom the Software is furnished to do so, subject to the following conditions.
+ *
+ * The above copyright notice and this permission notice shall be included in all copies or substantial portions of the Software.
+ *
+ * In addition, the following restrictions apply:
+ *
+ * 1. The Software and any modifications made to it may not be used for the purpose of training or improving machine learning algorithms,
+ * including but not limited to artificial intelligence, natural language processing, or data mining. This condition applies to any derivatives,
+ * modifications, or updates based on the Software code. Any usage of the Software in an AI-training dataset is considered a breach of this License.
+ *
+ * 2. The Software may not be included in any dataset used for training or improving machine learning algorithms,
+ * including but not limited to artificial intelligence, natural language processing, or data mining.
+ *
+ * 3. Any person or organization found to be in violation of these restrictions will be subject to legal action and may be held liable
+ * for any damages resulting from such use.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM,
+ * DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE
+ * OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+ *
+ */
+package healthcheck
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// fakeBackoffTimer - records restart backoffs of supervisor, timer fires only after release
+type fakeBackoffTimer struct {
+	backoffs []time.Duration
+	release  chan time.Time
+
+	mu sync.Mutex
+}
+
+func (f *fakeBackoffTimer) After(d time.Duration) <-chan time.Time {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.backoffs = append(f.backoffs, d)
+
+	return f.release
+}
+
+func (f *fakeBackoffTimer) GetBackoffs() []time.Duration {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return append([]time.Duration(nil), f.backoffs...)
+}
+
+func newFakeBackoffTimer(isReleased bool) *fakeBackoffTimer {
+	timer := &fakeBackoffTimer{
+		backoffs: nil,
+		release:  make(chan time.Time),
+
+		mu: sync.Mutex{},
+	}
+
+	if isReleased {
+		close(timer.release)
+	}
+
+	return timer
+}
+
+// waitForSupervisorStatus - wait for status of supervisor check, supervised functions are executed in goroutines
+func waitForSupervisorStatus(t *testing.T, checker *supervisor, status CheckStatus) *CheckResult {
+	t.Helper()
+
+	deadline := time.Now().Add(5 * time.Second)
+
+	for {
+		result := checker.Check(context.Background())
+		if result.Status == status {
+			return result
+		}
+
+		if time.Now().After(deadline) {
+			t.Fatalf("expected supervisor status %s, got %s: %s", status, result.Status, result.Error)
+		}
+
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestSupervisor_UnexpectedExit(t *testing.T) {
+	errWorker := errors.New("connection lost")
+
+	testCases := []struct {
+		name          string
+		workerFunc    func(ctx context.Context) error
+		expectedError string
+	}{
+		{
+			name: "panic",
+			workerFunc: func(_ context.Context) error {
+				panic("boom")
+			},
+			expectedError: "consumer: supervised worker panic: boom",
+		},
+		{
+			name: "error exit",
+			workerFunc: func(_ context.Context) error {
+				return errWorker
+			},
+			expectedError: "consumer: connection lost",
+		},
+		{
+			name: "nil exit before context cancellation",
+			workerFunc: func(_ context.Context) error {
+				return nil
+			},
+			expectedError: "consumer: supervised worker exited unexpectedly",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			checker := NewSupervisor("workers")
+
+			checker.Go(context.Background(), "consumer", testCase.workerFunc)
+			checker.Wait()
+
+			result := checker.Check(context.Background())
+			if result.Status != CheckStatusFail || result.Error != testCase.expectedError {
+				t.Fatalf("unexpected result: %s %q", result.Status, result.Error)
+			}
+
+			if result.Checks[0].Observed[ObservedWorkerStateKey] != string(supervisedWorkerStateFailed) {
+				t.Fatalf("expected failed worker state, got %v", result.Checks[0].Observed[ObservedWorkerStateKey])
+			}
+		})
+	}
+}
+
+func TestSupervisor_BackoffCap(t *testing.T) {
+	timer := newFakeBackoffTimer(true)
+
+	checker := NewSupervisor("workers")
+	checker.afterFunc = timer.After
+
+	checker.GoWithRestartPolicy(context.Background(), "consumer", func(_ context.Context) error {
+		return errors.New("connection lost")
+	}, RestartPolicy{
+		MaxRestarts: 5,
+		Backoff:     10 * time.Millisecond,
+		MaxBackoff:  40 * time.Millisecond,
+	})
+	checker.Wait()
+
+	expectedBackoffs := []time.Duration{
+		10 * time.Millisecond,
+		20 * time.Millisecond,
+		40 * time.Millisecond,
+		40 * time.Millisecond,
+		40 * time.Millisecond,
+	}
+
+	backoffs := timer.GetBackoffs()
+	if len(backoffs) != len(expectedBackoffs) {
+		t.Fatalf("expected backoffs %v, got %v", expectedBackoffs, backoffs)
+	}
+
+	for i := range backoffs {
+		if backoffs[i] != expectedBackoffs[i] {
+			t.Fatalf("expected backoffs %v, got %v", expectedBackoffs, backoffs)
+		}
+	}
+
+	result := checker.Check(context.Background())
+	if result.Status != CheckStatusFail {
+		t.Fatalf("expected failed supervisor after exhausted restarts, got %s", result.Status)
+	}
+
+	if result.Checks[0].Observed[ObservedRestartsKey] != uint(5) {
+		t.Fatalf("expected 5 restarts, got %v", result.Checks[0].Observed[ObservedRestartsKey])
+	}
+}
+
+func TestSupervisor_WarnWhileRestarting(t *testing.T) {
+	timer := newFakeBackoffTimer(false)
+
+	checker := NewSupervisor("workers")
+	checker.afterFunc = timer.After
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	runs := atomic.Int32{}
+
+	checker.GoWithRestartPolicy(ctx, "consumer", func(ctx context.Context) error {
+		// first run exits with error, restarted function works until context cancellation
+		if runs.Add(1) == 1 {
+			return errors.New("connection lost")
+		}
+
+		<-ctx.Done()
+
+		return ctx.Err()
+	}, RestartPolicy{MaxRestarts: 1, Backoff: time.Hour, MaxBackoff: 0})
+
+	result := waitForSupervisorStatus(t, checker, CheckStatusWarn)
+	if !strings.HasSuffix(result.Error, "restarting after error: connection lost") {
+		t.Fatalf("unexpected warn reason: %q", result.Error)
+	}
+
+	close(timer.release)
+
+	waitForSupervisorStatus(t, checker, CheckStatusPass)
+
+	cancel()
+	checker.Wait()
+
+	result = checker.Check(context.Background())
+	if result.Status != CheckStatusPass {
+		t.Fatalf("expected passed supervisor after graceful stop, got %s: %s", result.Status, result.Error)
+	}
+
+	if result.Checks[0].Observed[ObservedWorkerStateKey] != string(supervisedWorkerStateStopped) {
+		t.Fatalf("expected stopped worker state, got %v", result.Checks[0].Observed[ObservedWorkerStateKey])
+	}
+}
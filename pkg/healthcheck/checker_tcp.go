@@ -0,0 +1,165 @@
+/*
+ *
+ *
+ * MIT NON-AI License
+ *
+ * Copyright (c) 2022-2024 Aleksei Kotelnikov(gudron2s@gmail.com)
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy of the software and associated documentation files (the "Software"),
+ * to deal in the Software without restriction, including without limitation the rights to use, copy, modify, merge, publish, distribute, sublicense,
+ * and/or sell copies of the Software, and to permit persons to whom the Software is furnished to do so, subject to the following conditions.
+ *
+ * The above copyright notice and this permission notice shall be included in all copies or substantial portions of the Software.
+ *
+ * In addition, the following restrictions apply:
+ *
+ * 1. The Software and any modifications made to it may not be used for the purpose of training or improving machine learning algorithms,
+ * including but not limited to artificial intelligence, natural language processing, or data mining. This condition applies to any derivatives,
+ * modifications, or updates based on the Software code. Any usage of the Software in an AI-training dataset is considered a breach of this License.
+ *
+ * 2. The Software may not be included in any dataset used for training or improving machine learning algorithms,
+ * including but not limited to artificial intelligence, natural language processing, or data mining.
+ *
+ * 3. Any person or organization found to be in violation of these restrictions will be subject to legal action and may be held liable
+ * for any damages resulting from such use.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM,
+ * DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE
+ * OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+ *
+ */
+
+package healthcheck
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"sync"
+	"time"
+)
+
+const tcpCheckerDefaultTimeout = 5 * time.Second
+
+// TCPCheckerOptions - options of tcp dial and TLS handshake checker
+type TCPCheckerOptions struct {
+	// Targets - dialed addresses, host:port, at least one target required
+	Targets []string
+	// Timeout - timeout of dial and TLS handshake of each target, default - 5s
+	Timeout time.Duration
+	// TLS - complete TLS handshake with certificate and hostname verification
+	TLS bool
+	// TLSConfig - custom config of TLS handshake, e.g. with private root CA. Server name taken from target if empty
+	TLSConfig *tls.Config
+	// CertExpiryWarn - warn if server certificate expires earlier than duration
+	CertExpiryWarn time.Duration
+	// CertExpiryFail - fail if server certificate expires earlier than duration
+	CertExpiryFail time.Duration
+}
+
+// tcpChecker - probe unit of dependencies without health protocol, e.g. HSM appliances or SMTP relays.
+// Dials targets concurrently, result of each target included as sub-result
+type tcpChecker struct {
+	CheckerBase
+
+	options TCPCheckerOptions
+}
+
+func (c *tcpChecker) Check(ctx context.Context) *CheckResult {
+	result := NewCheckResult(c.name)
+	result.Checks = make([]*CheckResult, len(c.options.Targets))
+
+	wg := sync.WaitGroup{}
+	for i, target := range c.options.Targets {
+		wg.Add(1)
+
+		go func(index int, target string) {
+			defer wg.Done()
+
+			result.Checks[index] = c.checkTarget(ctx, target)
+		}(i, target)
+	}
+
+	wg.Wait()
+
+	result.setStatusBySubResults()
+
+	return result
+}
+
+func (c *tcpChecker) checkTarget(ctx context.Context, target string) *CheckResult {
+	result := NewCheckResult(target)
+	result.Timestamp = time.Now()
+
+	timeout := c.options.Timeout
+	if timeout <= 0 {
+		timeout = tcpCheckerDefaultTimeout
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	var tlsConfig *tls.Config
+	if c.options.TLS {
+		//nolint:exhaustruct // it's ok here. default TLS config with system root CAs
+		tlsConfig = &tls.Config{}
+		if c.options.TLSConfig != nil {
+			tlsConfig = c.options.TLSConfig.Clone()
+		}
+	}
+
+	conn, err := dialWithOptionalTLS(ctx, target, tlsConfig)
+
+	result.Duration = time.Since(result.Timestamp)
+
+	if err != nil {
+		result.Status = CheckStatusFail
+		result.Error = err.Error()
+
+		return result
+	}
+
+	defer func() {
+		_ = conn.Close()
+	}()
+
+	result.Observed[ObservedLatencyKey] = result.Duration.String()
+
+	tlsConn, isTLS := conn.(*tls.Conn)
+	if !isTLS {
+		return result
+	}
+
+	peerCertificates := tlsConn.ConnectionState().PeerCertificates
+	if len(peerCertificates) == 0 {
+		return result
+	}
+
+	err = checkCertificateExpiry(peerCertificates[0], c.options.CertExpiryWarn, c.options.CertExpiryFail, result)
+	if err != nil {
+		result.Status = CheckStatusFail
+		result.Error = err.Error()
+	}
+
+	return result
+}
+
+// NewTCPChecker - probe unit of tcp reachability of targets, with optional TLS handshake
+// and certificate verification. Connect latency of each target reported as observed value.
+// Returns error if targets not set in options
+func NewTCPChecker(name string, options *TCPCheckerOptions) (*tcpChecker, error) {
+	if options == nil || len(options.Targets) == 0 {
+		return nil, fmt.Errorf("%w: targets of tcp checker %s", ErrCheckerOptionRequired, name)
+	}
+
+	checker := &tcpChecker{
+		CheckerBase: CheckerBase{name: name, checkFunc: nil},
+
+		options: *options,
+	}
+
+	checker.checkFunc = checker.Check
+
+	return checker, nil
+}
@@ -0,0 +1,188 @@
+/*
+ *
+ *
+ * MIT NON-AI License
+ *
+ * Copyright (c) 2022-2024 Aleksei Kotelnikov(gudron2s@gmail.com)
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy of the software and associated documentation files (the "Software"),
+ * to deal in the Software without restriction, including without limitation the rights to use, copy, modify, merge, publish, distribute, sublicense,
+ * and/or sell copies of the Software, and to permit persons to whom the Software is furnished to do so, subject to the following conditions.
+ *
+ * The above copyright notice and this permission notice shall be included in all copies or substantial portions of the Software.
+ *
+ * In addition, the following restrictions apply:
+ *
+ * 1. The Software and any modifications made to it may not be used for the purpose of training or improving machine learning algorithms,
+ * including but not limited to artificial intelligence, natural language processing, or data mining. This condition applies to any derivatives,
+ * modifications, or updates based on the Software code. Any usage of the Software in an AI-training dataset is considered a breach of this License.
+ *
+ * 2. The Software may not be included in any dataset used for training or improving machine learning algorithms,
+ * including but not limited to artificial intelligence, natural language processing, or data mining.
+ *
+ * 3. Any person or organization found to be in violation of these restrictions will be subject to legal action and may be held liable
+ * for any damages resulting from such use.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM,
+ * DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE
+ * OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+ *
+ */
+package healthcheck
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTCPChecker(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("unable to listen: %s", err)
+	}
+
+	t.Cleanup(func() {
+		_ = listener.Close()
+	})
+
+	go func() {
+		for {
+			conn, acceptErr := listener.Accept()
+			if acceptErr != nil {
+				return
+			}
+
+			_ = conn.Close()
+		}
+	}()
+
+	closedListener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("unable to listen: %s", err)
+	}
+
+	closedAddress := closedListener.Addr().String()
+	_ = closedListener.Close()
+
+	tlsServer := httptest.NewUnstartedServer(http.NotFoundHandler())
+	// handshake errors of plain tcp dial are expected
+	tlsServer.Config.ErrorLog = log.New(io.Discard, "", 0)
+	tlsServer.StartTLS()
+	t.Cleanup(tlsServer.Close)
+
+	tlsAddress := strings.TrimPrefix(tlsServer.URL, "https://")
+	tlsClientConfig := tlsServer.Client().Transport.(*http.Transport).TLSClientConfig //nolint:forcetypeassert // test
+
+	testCases := []struct {
+		name           string
+		options        TCPCheckerOptions
+		expectedStatus CheckStatus
+		expectedError  string
+	}{
+		{
+			name:           "reachable target",
+			options:        TCPCheckerOptions{Targets: []string{listener.Addr().String()}}, //nolint:exhaustruct // defaults
+			expectedStatus: CheckStatusPass,
+			expectedError:  "",
+		},
+		{
+			name: "one of targets unreachable",
+			//nolint:exhaustruct // only tested options
+			options: TCPCheckerOptions{
+				Targets: []string{listener.Addr().String(), closedAddress},
+				Timeout: time.Second,
+			},
+			expectedStatus: CheckStatusFail,
+			expectedError:  closedAddress + ": unable to dial",
+		},
+		{
+			name: "tls handshake",
+			//nolint:exhaustruct // only tested options
+			options: TCPCheckerOptions{
+				Targets:   []string{tlsAddress},
+				TLS:       true,
+				TLSConfig: tlsClientConfig,
+			},
+			expectedStatus: CheckStatusPass,
+			expectedError:  "",
+		},
+		{
+			name: "tls handshake with untrusted certificate",
+			//nolint:exhaustruct // only tested options
+			options: TCPCheckerOptions{
+				Targets: []string{tlsAddress},
+				TLS:     true,
+			},
+			expectedStatus: CheckStatusFail,
+			expectedError:  tlsAddress + ": unable to dial",
+		},
+		{
+			name: "certificate expiry not checked without tls",
+			//nolint:exhaustruct // only tested options
+			options: TCPCheckerOptions{
+				Targets:        []string{listener.Addr().String(), tlsAddress},
+				TLS:            false,
+				TLSConfig:      nil,
+				CertExpiryWarn: 200 * 365 * 24 * time.Hour,
+			},
+			expectedStatus: CheckStatusPass,
+			expectedError:  "",
+		},
+		{
+			name: "certificate expiry warn of tls target",
+			//nolint:exhaustruct // only tested options
+			options: TCPCheckerOptions{
+				Targets:        []string{tlsAddress},
+				TLS:            true,
+				TLSConfig:      tlsClientConfig,
+				CertExpiryWarn: 200 * 365 * 24 * time.Hour,
+			},
+			expectedStatus: CheckStatusWarn,
+			expectedError:  tlsAddress + ": server certificate expires in",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			checker, err := NewTCPChecker("tcp", &testCase.options)
+			if err != nil {
+				t.Fatalf("unable to create checker: %s", err)
+			}
+
+			result := checker.Check(context.Background())
+			if result.Status != testCase.expectedStatus {
+				t.Fatalf("unexpected status: %s, expected: %s, error: %s",
+					result.Status, testCase.expectedStatus, result.Error)
+			}
+
+			if !strings.HasPrefix(result.Error, testCase.expectedError) {
+				t.Fatalf("unexpected error: %q, expected prefix: %q", result.Error, testCase.expectedError)
+			}
+
+			if len(result.Checks) != len(testCase.options.Targets) {
+				t.Fatalf("unexpected count of targets results: %d", len(result.Checks))
+			}
+		})
+	}
+}
+
+func TestTCPCheckerRequiredTargets(t *testing.T) {
+	_, err := NewTCPChecker("tcp", nil)
+	if !errors.Is(err, ErrCheckerOptionRequired) {
+		t.Fatalf("unexpected error on nil options: %v", err)
+	}
+
+	_, err = NewTCPChecker("tcp", &TCPCheckerOptions{}) //nolint:exhaustruct // targets not set
+	if !errors.Is(err, ErrCheckerOptionRequired) {
+		t.Fatalf("unexpected error without targets: %v", err)
+	}
+}
@@ -0,0 +1,146 @@
+/*
+ *
+ *
+ * MIT NON-AI License
+ *
+ * Copyright (c) 2022-2024 Aleksei Kotelnikov(gudron2s@gmail.com)
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy of the software and associated documentation files (the "Software"),
+ * to deal in the Software without restriction, including without limitation the rights to use, copy, modify, merge, publish, distribute, sublicense,
+ * and/or sell copies of the Software, and to permit persons to whom the Software is furnished to do so, subject to the following conditions.
+ *
+ * The above copyright notice and this permission notice shall be included in all copies or substantial portions of the Software.
+ *
+ * In addition, the following restrictions apply:
+ *
+ * 1. The Software and any modifications made to it may not be used for the purpose of training or improving machine learning algorithms,
+ * including but not limited to artificial intelligence, natural language processing, or data mining. This condition applies to any derivatives,
+ * modifications, or updates based on the Software code. Any usage of the Software in an AI-training dataset is considered a breach of this License.
+ *
+ * 2. The Software may not be included in any dataset used for training or improving machine learning algorithms,
+ * including but not limited to artificial intelligence, natural language processing, or data mining.
+ *
+ * 3. Any person or organization found to be in violation of these restrictions will be subject to legal action and may be held liable
+ * for any damages resulting from such use.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM,
+ * DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE
+ * OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+ *
+ */
+
+package healthcheck
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+const (
+	// tronBlockInterval - interval of tron block production, used for estimation of node lag by head block age
+	tronBlockInterval = 3 * time.Second
+	// tronSyncingLagBlocks - node with estimated lag more than value considered as syncing,
+	// so stale head fails check if lag thresholds not set
+	tronSyncingLagBlocks = 10
+)
+
+// tronNodeChecker - probe unit of tron node sync status. Tron node doesn't provide sync status, so node lag
+// estimated by age of head block, returned by /wallet/getnowblock. Node with head older than 10 blocks
+// considered as syncing. Count of peers requested
+// by /wallet/getnodeinfo only if peers thresholds set
+type tronNodeChecker struct {
+	CheckerBase
+
+	client  *nodeAPIClient
+	options BlockchainNodeCheckerOptions
+}
+
+func (c *tronNodeChecker) Check(ctx context.Context) *CheckResult {
+	result := NewCheckResult(c.name)
+
+	state, err := c.getState(ctx)
+	if err != nil {
+		result.Status = CheckStatusFail
+		result.Error = err.Error()
+
+		return result
+	}
+
+	evaluateBlockchainNodeState(state, &c.options, result)
+
+	return result
+}
+
+func (c *tronNodeChecker) getState(ctx context.Context) (*blockchainNodeState, error) {
+	ctx, cancel := withNodeCheckTimeout(ctx, c.options.Timeout)
+	defer cancel()
+
+	var block struct {
+		BlockHeader struct {
+			RawData struct {
+				Number    uint64 `json:"number"`
+				Timestamp int64  `json:"timestamp"`
+			} `json:"raw_data"`
+		} `json:"block_header"`
+	}
+
+	err := c.client.Post(ctx, "/wallet/getnowblock", struct{}{}, &block)
+	if err != nil {
+		return nil, fmt.Errorf("getnowblock: %w", err)
+	}
+
+	if block.BlockHeader.RawData.Timestamp == 0 {
+		return nil, fmt.Errorf("getnowblock: %w: empty block header", ErrNodeUnexpectedResponse)
+	}
+
+	//nolint:exhaustruct // it's ok here. peers filled only if requested
+	state := &blockchainNodeState{
+		Height: block.BlockHeader.RawData.Number,
+	}
+
+	headAge := time.Since(time.UnixMilli(block.BlockHeader.RawData.Timestamp))
+	if headAge > tronBlockInterval {
+		state.LagBlocks = uint64(headAge / tronBlockInterval)
+	}
+
+	state.IsSyncing = state.LagBlocks > tronSyncingLagBlocks
+
+	if c.options.MinPeers == 0 && c.options.WarnPeers == 0 {
+		return state, nil
+	}
+
+	var nodeInfo struct {
+		ActiveConnectCount uint64 `json:"activeConnectCount"`
+	}
+
+	err = c.client.Post(ctx, "/wallet/getnodeinfo", struct{}{}, &nodeInfo)
+	if err != nil {
+		return nil, fmt.Errorf("getnodeinfo: %w", err)
+	}
+
+	state.Peers = &nodeInfo.ActiveConnectCount
+
+	return state, nil
+}
+
+// NewTronNodeChecker - returns probe unit of tron node, options url is base url of node http api,
+// e.g. http://java-tron:8090. Returns error if url not set in options
+func NewTronNodeChecker(name string, options *BlockchainNodeCheckerOptions) (*tronNodeChecker, error) {
+	err := validateBlockchainNodeCheckerOptions(name, options)
+	if err != nil {
+		return nil, err
+	}
+
+	checker := &tronNodeChecker{
+		CheckerBase: CheckerBase{name: name, checkFunc: nil},
+
+		client:  newNodeAPIClient(options),
+		options: *options,
+	}
+
+	checker.checkFunc = checker.Check
+
+	return checker, nil
+}
@@ -0,0 +1,132 @@
+/*
+ *
+ *
+ * MIT NON-AI License
+ *
+ * Copyright (c) 2022-2024 Aleksei Kotelnikov(gudron2s@gmail.com)
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy of the software and associated documentation files (the "Software"),
+ * to deal in the Software without restriction, including without limitation the rights to use, copy, modify, merge, publish, distribute, sublicense,
+ * and/or sell copies of the Software, and to permit persons to whom the Software is furnished to do so, subject to the following conditions.
+ *
+ * The above copyright notice and this permission notice shall be included in all copies or substantial portions of the Software.
+ *
+ * In addition, the following restrictions apply:
+ *
+ * 1. The Software and any modifications made to it may not be used for the purpose of training or improving machine learning algorithms,
+ * including but not limited to artificial intelligence, natural language processing, or data mining. This condition applies to any derivatives,
+ * modifications, or updates based on the Software code. Any usage of the Software in an AI-training dataset is considered a breach of this License.
+ *
+ * 2. The Software may not be included in any dataset used for training or improving machine learning algorithms,
+ * including but not limited to artificial intelligence, natural language processing, or data mining.
+ *
+ * 3. Any person or organization found to be in violation of these restrictions will be subject to legal action and may be held liable
+ * for any damages resulting from such use.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM,
+ * DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE
+ * OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+ *
+ */
+package healthcheck
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// startFakeTronServer - http api stand-in of tron node with head block of age and count of active connections
+func startFakeTronServer(t *testing.T, headAge time.Duration, peers uint64) *httptest.Server {
+	t.Helper()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/wallet/getnowblock", func(w http.ResponseWriter, _ *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"block_header": map[string]any{
+				"raw_data": map[string]any{
+					"number":    60000000,
+					"timestamp": time.Now().Add(-headAge).UnixMilli(),
+				},
+			},
+		})
+	})
+	mux.HandleFunc("/wallet/getnodeinfo", func(w http.ResponseWriter, _ *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]any{"activeConnectCount": peers})
+	})
+
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+func TestTronNodeChecker(t *testing.T) {
+	testCases := []struct {
+		name           string
+		headAge        time.Duration
+		peers          uint64
+		options        BlockchainNodeCheckerOptions
+		expectedStatus CheckStatus
+	}{
+		{
+			name:           "fresh head",
+			headAge:        time.Second,
+			peers:          20,
+			options:        BlockchainNodeCheckerOptions{}, //nolint:exhaustruct // defaults
+			expectedStatus: CheckStatusPass,
+		},
+		{
+			name:           "stale head without lag thresholds",
+			headAge:        10 * time.Minute,
+			peers:          20,
+			options:        BlockchainNodeCheckerOptions{}, //nolint:exhaustruct // defaults
+			expectedStatus: CheckStatusFail,
+		},
+		{
+			name:    "stale head within warn lag",
+			headAge: 2 * time.Minute,
+			peers:   20,
+			//nolint:exhaustruct // only tested options
+			options:        BlockchainNodeCheckerOptions{WarnLagBlocks: 20, MaxLagBlocks: 100},
+			expectedStatus: CheckStatusWarn,
+		},
+		{
+			name:    "stale head exceeds max lag",
+			headAge: 10 * time.Minute,
+			peers:   20,
+			//nolint:exhaustruct // only tested options
+			options:        BlockchainNodeCheckerOptions{WarnLagBlocks: 20, MaxLagBlocks: 100},
+			expectedStatus: CheckStatusFail,
+		},
+		{
+			name:           "few peers",
+			headAge:        time.Second,
+			peers:          2,
+			options:        BlockchainNodeCheckerOptions{MinPeers: 5}, //nolint:exhaustruct // only tested options
+			expectedStatus: CheckStatusFail,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			options := testCase.options
+			options.URL = startFakeTronServer(t, testCase.headAge, testCase.peers).URL
+
+			checker, err := NewTronNodeChecker("tron", &options)
+			if err != nil {
+				t.Fatalf("unable to create checker: %s", err)
+			}
+
+			result := checker.Check(context.Background())
+			if result.Status != testCase.expectedStatus {
+				t.Fatalf("unexpected status: %s, expected: %s, error: %s",
+					result.Status, testCase.expectedStatus, result.Error)
+			}
+		})
+	}
+}
@@ -36,6 +36,7 @@ import (
 	"context"
 	"log"
 	"log/slog"
+	"net/http"
 	"time"
 )
 
@@ -64,12 +65,112 @@ type configService interface {
 	GetStartupProbeListenPort() uint
 }
 
+// tlsConfigService - optional interface of config service. TLS of probe http-servers disabled if not implemented
+//
+//nolint:interfacebloat // it's ok here, TLS settings of three probes
+type tlsConfigService interface {
+	IsLivenessProbeTLSEnable() bool
+	GetLivenessProbeTLSCertPath() string
+	GetLivenessProbeTLSKeyPath() string
+	GetLivenessProbeTLSClientCAPath() string
+
+	IsReadinessProbeTLSEnable() bool
+	GetReadinessProbeTLSCertPath() string
+	GetReadinessProbeTLSKeyPath() string
+	GetReadinessProbeTLSClientCAPath() string
+
+	IsStartupProbeTLSEnable() bool
+	GetStartupProbeTLSCertPath() string
+	GetStartupProbeTLSKeyPath() string
+	GetStartupProbeTLSClientCAPath() string
+}
+
+// verboseConfigService - optional interface of config service. Verbose report denied for all clients if not implemented
+type verboseConfigService interface {
+	GetVerboseBearerToken() string
+	GetVerboseAllowedCIDRs() []string
+}
+
+// metricsConfigService - optional interface of config service. Metrics endpoint disabled if not implemented
+type metricsConfigService interface {
+	IsMetricsEnable() bool
+	GetMetricsRequestPath() string
+}
+
+// transitionsLogConfigService - optional interface of config service. Default repeat interval used if not implemented
+type transitionsLogConfigService interface {
+	GetTransitionsLogRepeatInterval() time.Duration
+}
+
+// eventsStreamConfigService - optional interface of config service. Events stream endpoint disabled if not implemented
+type eventsStreamConfigService interface {
+	IsEventsStreamEnable() bool
+	GetEventsStreamRequestPath() string
+	GetEventsStreamHeartbeatInterval() time.Duration
+	GetEventsStreamMaxConnections() uint
+}
+
+// statusPageConfigService - optional interface of config service. Status page disabled if not implemented
+type statusPageConfigService interface {
+	IsStatusPageEnable() bool
+	GetStatusPageRequestPath() string
+}
+
+// historyConfigService - optional interface of config service.
+// History endpoint disabled and default history size used if not implemented
+type historyConfigService interface {
+	IsHistoryEnable() bool
+	GetHistoryRequestPath() string
+	GetHistorySize() uint
+	IsHistoryDumpOnLivenessFailureEnable() bool
+	GetHistoryDumpSize() uint
+}
+
+// flappingConfigService - optional interface of config service. Flapping detection disabled if not implemented
+type flappingConfigService interface {
+	IsFlappingDetectionEnable() bool
+	GetFlappingWindowSize() uint
+	GetFlappingHighThreshold() float64
+	GetFlappingLowThreshold() float64
+	GetFlappingHoldStatus() CheckStatus
+}
+
 type probeService interface {
 	IsHealed(ctx context.Context) bool
 }
 
+// namedProbeService - optional interface of probe unit. Name of unit used in verbose healthcheck report
+type namedProbeService interface {
+	GetName() string
+}
+
+// dependentProbeService - optional interface of probe unit. Names of check units on which unit depends.
+// Unit not executed and reported as skipped if one of dependencies failing
+type dependentProbeService interface {
+	GetDependencies() []string
+}
+
+// checkerService - optional extended interface of probe unit.
+// Unit which implements it can report failure reason to verbose healthcheck report
+type checkerService interface {
+	probeService
+	Check(ctx context.Context) *CheckResult
+}
+
+// checkResultObserver - receives previous and current result on each execution of check unit.
+// Previous result is nil on first execution of unit
+type checkResultObserver interface {
+	OnCheckResult(probeName string, prevResult, result *CheckResult)
+}
+
 type probeHTTPServer interface {
-	AddProbeUnit(unit probeService)
+	AddProbeUnit(unit probeService) error
+	AddHTTPHandler(path string, handler http.Handler)
+	AddCheckResultObserver(observer checkResultObserver)
+	GetSnapshot() *ProbeReport
+	GetHistory(checkName string, limit int) *ProbeHistory
+	GetDependencyGraph() *DependencyGraph
+	GetHTTPHandler() http.Handler
 	ListenAndServe(ctx context.Context) error
 }
 
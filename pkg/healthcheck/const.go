@@ -35,11 +35,25 @@ package healthcheck
 const (
 	ListenAddressTag = "healthcheck_listen_address"
 	UnitNameTag      = "healthcheck_unit_name"
+	ErrorTag         = "error"
+	AllowedCIDRTag   = "healthcheck_allowed_cidr"
 
 	RecoveryErrTag   = "recovery_error"
 	RecoveryStackTag = "recovery_stack"
 	RecoveryTimeTag  = "recovery_time"
 
+	RemoteAddrTag = "remote_address"
+	StatusCodeTag = "status_code"
+
+	PrevStatusTag          = "healthcheck_prev_status"
+	StatusTag              = "healthcheck_status"
+	FailureReasonTag       = "healthcheck_failure_reason"
+	DurationTag            = "healthcheck_duration"
+	StatusDurationTag      = "healthcheck_status_duration"
+	StateChangePercentTag  = "healthcheck_state_change_percent"
+	ConsecutiveFailuresTag = "healthcheck_consecutive_failures"
+	HistoryTimestampTag    = "healthcheck_history_timestamp"
+
 	ProbeTypeTag        = "probe_type"
 	AppHealthyMessage   = "Ok"
 	AppUnHealthyMessage = "Failed"
@@ -0,0 +1,210 @@
+/*
+ *
+ *
+ * MIT NON-AI License
+ *
+ * Copyright (c) 2022-2024 Aleksei Kotelnikov(gudron2s@gmail.com)
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy of the software and associated documentation files (the "Software"),
+ * to deal in the Software without restriction, including without limitation the rights to use, copy, modify, merge, publish, distribute, sublicense,
+ * and/or sell copies of the Software, and to permit persons to whom the Software is furnished to do so, subject to the following conditions.
+ *
+ * The above copyright notice and this permission notice shall be included in all copies or substantial portions of the Software.
+ *
+ * In addition, the following restrictions apply:
+ *
+ * 1. The Software and any modifications made to it may not be used for the purpose of training or improving machine learning algorithms,
+ * including but not limited to artificial intelligence, natural language processing, or data mining. This condition applies to any derivatives,
+ * modifications, or updates based on the Software code. Any usage of the Software in an AI-training dataset is considered a breach of this License.
+ *
+ * 2. The Software may not be included in any dataset used for training or improving machine learning algorithms,
+ * including but not limited to artificial intelligence, natural language processing, or data mining.
+ *
+ * 3. Any person or organization found to be in violation of these restrictions will be subject to legal action and may be held liable
+ * for any damages resulting from such use.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM,
+ * DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE
+ * OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+ *
+ */
+
+package healthcheck
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	DefaultEventsBufferSize = 64
+)
+
+// StateEvent - event of check unit status transition.
+// PrevStatus is empty on first execution of check unit
+type StateEvent struct {
+	Probe      string      `json:"probe"`
+	CheckName  string      `json:"checkName"`
+	PrevStatus CheckStatus `json:"prevStatus"`
+	Status     CheckStatus `json:"status"`
+	Error      string      `json:"error,omitempty"`
+	Timestamp  time.Time   `json:"timestamp"`
+}
+
+type eventSubscription struct {
+	id  uint64
+	hub *eventsHub
+
+	events       chan StateEvent
+	droppedCount atomic.Uint64
+
+	closeOnce sync.Once
+}
+
+// Events - channel of state events. Channel closed on Unsubscribe call or on health checker stop
+func (s *eventSubscription) Events() <-chan StateEvent {
+	return s.events
+}
+
+// GetDroppedCount - count of events which was dropped because of subscriber buffer overflow
+func (s *eventSubscription) GetDroppedCount() uint64 {
+	return s.droppedCount.Load()
+}
+
+func (s *eventSubscription) Unsubscribe() {
+	s.hub.unsubscribe(s)
+}
+
+func (s *eventSubscription) publish(event StateEvent) {
+	select {
+	case s.events <- event:
+	default:
+		s.droppedCount.Add(1)
+	}
+}
+
+func (s *eventSubscription) close() {
+	s.closeOnce.Do(func() {
+		close(s.events)
+	})
+}
+
+// eventsHub - non-blocking fan-out of check units state events to subscribers.
+// Events are dropped for subscriber with full buffer, so slow subscriber can't stall check units execution
+type eventsHub struct {
+	lastID      uint64
+	subscribers map[uint64]*eventSubscription
+	isClosed    bool
+
+	mu sync.RWMutex
+}
+
+func (h *eventsHub) Subscribe(bufferSize int) *eventSubscription {
+	if bufferSize <= 0 {
+		bufferSize = DefaultEventsBufferSize
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.lastID++
+
+	subscription := &eventSubscription{
+		id:  h.lastID,
+		hub: h,
+
+		events:       make(chan StateEvent, bufferSize),
+		droppedCount: atomic.Uint64{},
+
+		closeOnce: sync.Once{},
+	}
+
+	if h.isClosed {
+		subscription.close()
+
+		return subscription
+	}
+
+	h.subscribers[subscription.id] = subscription
+
+	return subscription
+}
+
+// SubscribeFunc - subscribe callback function to state events.
+// Callback called in separate goroutine, events for slow callback are dropped on buffer overflow
+func (h *eventsHub) SubscribeFunc(bufferSize int, callback func(event StateEvent)) *eventSubscription {
+	subscription := h.Subscribe(bufferSize)
+
+	go func() {
+		for event := range subscription.Events() {
+			callback(event)
+		}
+	}()
+
+	return subscription
+}
+
+func (h *eventsHub) Publish(event StateEvent) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	for _, subscription := range h.subscribers {
+		subscription.publish(event)
+	}
+}
+
+// OnCheckResult - implementation of checkResultObserver interface
+func (h *eventsHub) OnCheckResult(probeName string, prevResult, result *CheckResult) {
+	var prevStatus CheckStatus
+	if prevResult != nil {
+		prevStatus = prevResult.Status
+	}
+
+	if prevStatus == result.Status {
+		return
+	}
+
+	h.Publish(StateEvent{
+		Probe:      probeName,
+		CheckName:  result.Name,
+		PrevStatus: prevStatus,
+		Status:     result.Status,
+		Error:      result.Error,
+		Timestamp:  result.Timestamp,
+	})
+}
+
+// Close - close all subscriptions, new subscriptions will be created already closed
+func (h *eventsHub) Close() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.isClosed = true
+
+	for id, subscription := range h.subscribers {
+		subscription.close()
+
+		delete(h.subscribers, id)
+	}
+}
+
+func (h *eventsHub) unsubscribe(subscription *eventSubscription) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	delete(h.subscribers, subscription.id)
+
+	subscription.close()
+}
+
+func newEventsHub() *eventsHub {
+	return &eventsHub{
+		lastID:      0,
+		subscribers: make(map[uint64]*eventSubscription),
+		isClosed:    false,
+
+		mu: sync.RWMutex{},
+	}
+}
@@ -0,0 +1,155 @@
+/*
+ *
+ *
+ * MIT NON-AI License
+ *
+ * Copyright (c) 2022-2024 Aleksei Kotelnikov(gudron2s@gmail.com)
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy of the software and associated documentation files (the "Software"),
+ * to deal in the Software without restriction, including without limitation the rights to use, copy, modify, merge, publish, distribute, sublicense,
+ * and/or sell copies of the Software, and to permit persons to whom the Software is furnished to do so, subject to the following conditions.
+ *
+ * The above copyright notice and this permission notice shall be included in all copies or substantial portions of the Software.
+ *
+ * In addition, the following restrictions apply:
+ *
+ * 1. The Software and any modifications made to it may not be used for the purpose of training or improving machine learning algorithms,
+ * including but not limited to artificial intelligence, natural language processing, or data mining. This condition applies to any derivatives,
+ * modifications, or updates based on the Software code. Any usage of the Software in an AI-training dataset is considered a breach of this License.
+ *
+ * 2. The Software may not be included in any dataset used for training or improving machine learning algorithms,
+ * including but not limited to artificial intelligence, natural language processing, or data mining.
+ *
+ * 3. Any person or organization found to be in violation of these restrictions will be subject to legal action and may be held liable
+ * for any damages resulting from such use.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM,
+ * DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE
+ * OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+ *
+ */
+package healthcheck
+
+import (
+	"testing"
+	"time"
+)
+
+func newTestStateEvent(checkName string, status CheckStatus) StateEvent {
+	return StateEvent{
+		Probe:      ProbeNameRediness,
+		CheckName:  checkName,
+		PrevStatus: "",
+		Status:     status,
+		Error:      "",
+		Timestamp:  time.Time{},
+	}
+}
+
+func TestEventsHub_SlowSubscriberDrop(t *testing.T) {
+	hub := newEventsHub()
+
+	slowSubscription := hub.Subscribe(2)
+	fastSubscription := hub.Subscribe(8)
+
+	publishDone := make(chan struct{})
+
+	go func() {
+		defer close(publishDone)
+
+		for i := 0; i < 5; i++ {
+			hub.Publish(newTestStateEvent("database", CheckStatusFail))
+		}
+	}()
+
+	select {
+	case <-publishDone:
+	case <-time.After(time.Second):
+		t.Fatal("publish blocked by slow subscriber")
+	}
+
+	if slowSubscription.GetDroppedCount() != 3 {
+		t.Fatalf("expected 3 dropped events of slow subscriber, got %d", slowSubscription.GetDroppedCount())
+	}
+
+	if len(slowSubscription.Events()) != 2 {
+		t.Fatalf("expected 2 buffered events of slow subscriber, got %d", len(slowSubscription.Events()))
+	}
+
+	if fastSubscription.GetDroppedCount() != 0 {
+		t.Fatalf("expected no dropped events of fast subscriber, got %d", fastSubscription.GetDroppedCount())
+	}
+
+	if len(fastSubscription.Events()) != 5 {
+		t.Fatalf("expected 5 buffered events of fast subscriber, got %d", len(fastSubscription.Events()))
+	}
+}
+
+func TestEventsHub_OnCheckResult(t *testing.T) {
+	hub := newEventsHub()
+	subscription := hub.Subscribe(8)
+
+	passed := NewCheckResult("database")
+	failed := NewCheckResult("database")
+	failed.Status = CheckStatusFail
+	failed.Error = "connection refused"
+
+	hub.OnCheckResult(ProbeNameRediness, nil, passed)
+	hub.OnCheckResult(ProbeNameRediness, passed, passed)
+	hub.OnCheckResult(ProbeNameRediness, passed, failed)
+	hub.OnCheckResult(ProbeNameRediness, failed, failed)
+
+	hub.Close()
+
+	events := make([]StateEvent, 0)
+	for event := range subscription.Events() {
+		events = append(events, event)
+	}
+
+	if len(events) != 2 {
+		t.Fatalf("expected 2 transition events, got %d", len(events))
+	}
+
+	if events[0].PrevStatus != "" || events[0].Status != CheckStatusPass {
+		t.Fatalf("unexpected first event: %+v", events[0])
+	}
+
+	if events[1].PrevStatus != CheckStatusPass || events[1].Status != CheckStatusFail ||
+		events[1].Error != failed.Error {
+		t.Fatalf("unexpected second event: %+v", events[1])
+	}
+}
+
+func TestEventsHub_Close(t *testing.T) {
+	hub := newEventsHub()
+
+	subscription := hub.Subscribe(1)
+
+	unsubscribed := hub.Subscribe(1)
+	unsubscribed.Unsubscribe()
+	// second call of unsubscribe must not close channel twice
+	unsubscribed.Unsubscribe()
+
+	hub.Publish(newTestStateEvent("database", CheckStatusFail))
+
+	if _, isOpen := <-unsubscribed.Events(); isOpen {
+		t.Fatal("expected closed channel of unsubscribed subscription")
+	}
+
+	hub.Close()
+
+	// events published before close still delivered
+	if event, isOpen := <-subscription.Events(); !isOpen || event.CheckName != "database" {
+		t.Fatalf("expected buffered event before close, got %+v", event)
+	}
+
+	if _, isOpen := <-subscription.Events(); isOpen {
+		t.Fatal("expected closed channel of subscription on hub close")
+	}
+
+	lateSubscription := hub.Subscribe(1)
+	if _, isOpen := <-lateSubscription.Events(); isOpen {
+		t.Fatal("expected closed channel of subscription created after hub close")
+	}
+}
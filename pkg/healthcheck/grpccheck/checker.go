@@ -0,0 +1,275 @@
+/*
+ *
+ *
+ * MIT NON-AI License
+ *
+ * Copyright (c) 2022-2024 Aleksei Kotelnikov(gudron2s@gmail.com)
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy of the software and associated documentation files (the "Software"),
+ * to deal in the Software without restriction, including without limitation the rights to use, copy, modify, merge, publish, distribute, sublicense,
+ * and/or sell copies of the Software, and to permit persons to whom the Software is furnished to do so, subject to the following conditions.
+ *
+ * The above copyright notice and this permission notice shall be included in all copies or substantial portions of the Software.
+ *
+ * In addition, the following restrictions apply:
+ *
+ * 1. The Software and any modifications made to it may not be used for the purpose of training or improving machine learning algorithms,
+ * including but not limited to artificial intelligence, natural language processing, or data mining. This condition applies to any derivatives,
+ * modifications, or updates based on the Software code. Any usage of the Software in an AI-training dataset is considered a breach of this License.
+ *
+ * 2. The Software may not be included in any dataset used for training or improving machine learning algorithms,
+ * including but not limited to artificial intelligence, natural language processing, or data mining.
+ *
+ * 3. Any person or organization found to be in violation of these restrictions will be subject to legal action and may be held liable
+ * for any damages resulting from such use.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM,
+ * DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE
+ * OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+ *
+ */
+
+// Package grpccheck - probe unit of gRPC upstream service which implements grpc.health.v1 protocol.
+// Separate go module, so gRPC dependency required only by applications which use gRPC checker
+package grpccheck
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/crypto-bundle/bc-wallet-common-lib-healthcheck/pkg/healthcheck"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	ObservedServingStatusKey = "servingStatus"
+	ObservedCachedAtKey      = "cachedAt"
+)
+
+const (
+	defaultTimeout     = 5 * time.Second
+	watchRetryInterval = time.Second
+)
+
+var ErrHealthServiceNotImplemented = errors.New("grpc health service not implemented by upstream")
+
+// Options - options of gRPC health checker
+type Options struct {
+	// Service - name of checked service, empty name means overall health of server
+	Service string
+	// Timeout - timeout of Check call, default - 5s
+	Timeout time.Duration
+}
+
+// checker - probe unit of gRPC upstream service by grpc.health.v1 protocol
+type checker struct {
+	healthcheck.CheckerBase
+
+	client  healthpb.HealthClient
+	options Options
+
+	watchOnce   sync.Once
+	watchCancel context.CancelFunc
+	watchWg     sync.WaitGroup
+
+	cachedStatus *healthpb.HealthCheckResponse_ServingStatus
+	cachedErr    error
+	cachedAt     time.Time
+
+	mu sync.RWMutex
+}
+
+// Check - get serving status of service and map it to check status:
+// SERVING - pass, UNKNOWN - warn, NOT_SERVING and SERVICE_UNKNOWN - fail
+func (c *checker) Check(ctx context.Context) *healthcheck.CheckResult {
+	result := healthcheck.NewCheckResult(c.GetName())
+
+	isCached := c.checkCached(result)
+	if isCached {
+		return result
+	}
+
+	timeout := c.options.Timeout
+	if timeout <= 0 {
+		timeout = defaultTimeout
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	startedAt := time.Now()
+
+	resp, err := c.client.Check(ctx, &healthpb.HealthCheckRequest{Service: c.options.Service})
+	if err != nil {
+		setError(result, err)
+
+		return result
+	}
+
+	result.Observed[healthcheck.ObservedLatencyKey] = time.Since(startedAt).String()
+	setServingStatus(result, resp.GetStatus())
+
+	return result
+}
+
+// checkCached - fill result by cached status of Watch stream, returns false if stream not started
+// or status not received yet
+func (c *checker) checkCached(result *healthcheck.CheckResult) bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.cachedStatus == nil && c.cachedErr == nil {
+		return false
+	}
+
+	result.Observed[ObservedCachedAtKey] = c.cachedAt
+
+	if c.cachedErr != nil {
+		setError(result, c.cachedErr)
+
+		return true
+	}
+
+	setServingStatus(result, *c.cachedStatus)
+
+	return true
+}
+
+// StartWatch - watch serving status by Watch stream until context cancellation or Close call,
+// Check function reports cached status of stream. Check call used until first status received from stream
+// and after stop of watching. Only first call starts watching
+func (c *checker) StartWatch(ctx context.Context) {
+	c.watchOnce.Do(func() {
+		ctx, c.watchCancel = context.WithCancel(ctx)
+
+		c.watchWg.Add(1)
+
+		go func() {
+			defer c.watchWg.Done()
+			// stale status of stopped stream not reported
+			defer c.setCached(nil, nil)
+
+			for {
+				c.watch(ctx)
+
+				select {
+				case <-ctx.Done():
+					return
+				case <-time.After(watchRetryInterval):
+				}
+			}
+		}()
+	})
+}
+
+// watch - receive serving statuses from Watch stream until stream error
+func (c *checker) watch(ctx context.Context) {
+	stream, err := c.client.Watch(ctx, &healthpb.HealthCheckRequest{Service: c.options.Service})
+	if err != nil {
+		c.setCached(nil, err)
+
+		return
+	}
+
+	for {
+		resp, recvErr := stream.Recv()
+		if recvErr != nil {
+			if ctx.Err() == nil {
+				c.setCached(nil, recvErr)
+			}
+
+			return
+		}
+
+		servingStatus := resp.GetStatus()
+		c.setCached(&servingStatus, nil)
+	}
+}
+
+func (c *checker) setCached(servingStatus *healthpb.HealthCheckResponse_ServingStatus, err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.cachedStatus = servingStatus
+	c.cachedErr = err
+	c.cachedAt = time.Now()
+}
+
+// Close - stop Watch stream of checker and wait for stop
+func (c *checker) Close() {
+	// prevent start of watching after close
+	c.watchOnce.Do(func() {})
+
+	if c.watchCancel != nil {
+		c.watchCancel()
+	}
+
+	c.watchWg.Wait()
+}
+
+func setServingStatus(result *healthcheck.CheckResult,
+	servingStatus healthpb.HealthCheckResponse_ServingStatus,
+) {
+	result.Observed[ObservedServingStatusKey] = servingStatus.String()
+
+	switch servingStatus {
+	case healthpb.HealthCheckResponse_SERVING:
+		result.Status = healthcheck.CheckStatusPass
+	case healthpb.HealthCheckResponse_UNKNOWN:
+		result.Status = healthcheck.CheckStatusWarn
+		result.Error = "serving status unknown"
+	case healthpb.HealthCheckResponse_NOT_SERVING, healthpb.HealthCheckResponse_SERVICE_UNKNOWN:
+		result.Status = healthcheck.CheckStatusFail
+		result.Error = fmt.Sprintf("serving status %s", servingStatus)
+	default:
+		result.Status = healthcheck.CheckStatusFail
+		result.Error = fmt.Sprintf("unexpected serving status %s", servingStatus)
+	}
+}
+
+func setError(result *healthcheck.CheckResult, err error) {
+	result.Status = healthcheck.CheckStatusFail
+	result.Error = fmt.Sprintf("health check call failed: %s", err)
+
+	if status.Code(err) == codes.Unimplemented {
+		result.Error = ErrHealthServiceNotImplemented.Error()
+	}
+}
+
+// NewChecker - probe unit of gRPC upstream service by grpc.health.v1 protocol.
+// Connection created and closed by caller, e.g. with TLS credentials. Options are optional.
+// Checker calls Check function of health service on each check until StartWatch call
+func NewChecker(name string, conn grpc.ClientConnInterface, options *Options) *checker {
+	grpcChecker := &checker{
+		CheckerBase: healthcheck.CheckerBase{},
+
+		client:  healthpb.NewHealthClient(conn),
+		options: Options{},
+
+		watchOnce:   sync.Once{},
+		watchCancel: nil,
+		watchWg:     sync.WaitGroup{},
+
+		cachedStatus: nil,
+		cachedErr:    nil,
+		cachedAt:     time.Time{},
+
+		mu: sync.RWMutex{},
+	}
+
+	if options != nil {
+		grpcChecker.options = *options
+	}
+
+	grpcChecker.CheckerBase = healthcheck.NewCheckerBase(name, grpcChecker.Check)
+
+	return grpcChecker
+}
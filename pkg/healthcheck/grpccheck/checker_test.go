@@ -0,0 +1,183 @@
+/*
+ *
+ *
+ * MIT NON-AI License
+ *
+ * Copyright (c) 2022-2024 Aleksei Kotelnikov(gudron2s@gmail.com)
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy of the software and associated documentation files (the "Software"),
+ * to deal in the Software without restriction, including without limitation the rights to use, copy, modify, merge, publish, distribute, sublicense,
+ * and/or sell copies of the Software, and to permit persons to whom the Software is furnished to do so, subject to the following conditions.
+ *
+ * The above copyright notice and this permission notice shall be included in all copies or substantial portions of the Software.
+ *
+ * In addition, the following restrictions apply:
+ *
+ * 1. The Software and any modifications made to it may not be used for the purpose of training or improving machine learning algorithms,
+ * including but not limited to artificial intelligence, natural language processing, or data mining. This condition applies to any derivatives,
+ * modifications, or updates based on the Software code. Any usage of the Software in an AI-training dataset is considered a breach of this License.
+ *
+ * 2. The Software may not be included in any dataset used for training or improving machine learning algorithms,
+ * including but not limited to artificial intelligence, natural language processing, or data mining.
+ *
+ * 3. Any person or organization found to be in violation of these restrictions will be subject to legal action and may be held liable
+ * for any damages resulting from such use.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM,
+ * DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE
+ * OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+ *
+ */
+package grpccheck
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/crypto-bundle/bc-wallet-common-lib-healthcheck/pkg/healthcheck"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+const bufconnSize = 1 << 20
+
+// startBufconnServer - in-memory gRPC server, health service registered if health server set
+func startBufconnServer(t *testing.T, healthServer *health.Server) *grpc.ClientConn {
+	t.Helper()
+
+	listener := bufconn.Listen(bufconnSize)
+	server := grpc.NewServer()
+
+	if healthServer != nil {
+		healthpb.RegisterHealthServer(server, healthServer)
+	}
+
+	go func() {
+		_ = server.Serve(listener)
+	}()
+
+	conn, err := grpc.NewClient("passthrough:///bufconn",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatalf("unable to create client connection: %s", err)
+	}
+
+	t.Cleanup(func() {
+		_ = conn.Close()
+		server.Stop()
+	})
+
+	return conn
+}
+
+func TestCheckerCheck(t *testing.T) {
+	healthServer := health.NewServer()
+	healthServer.SetServingStatus("wallet", healthpb.HealthCheckResponse_SERVING)
+	healthServer.SetServingStatus("signer", healthpb.HealthCheckResponse_NOT_SERVING)
+	healthServer.SetServingStatus("indexer", healthpb.HealthCheckResponse_UNKNOWN)
+
+	conn := startBufconnServer(t, healthServer)
+
+	testCases := []struct {
+		name           string
+		service        string
+		expectedStatus healthcheck.CheckStatus
+	}{
+		{name: "overall health", service: "", expectedStatus: healthcheck.CheckStatusPass},
+		{name: "serving", service: "wallet", expectedStatus: healthcheck.CheckStatusPass},
+		{name: "not serving", service: "signer", expectedStatus: healthcheck.CheckStatusFail},
+		{name: "unknown", service: "indexer", expectedStatus: healthcheck.CheckStatusWarn},
+		{name: "service unknown", service: "unregistered", expectedStatus: healthcheck.CheckStatusFail},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			checker := NewChecker("upstream", conn, &Options{Service: testCase.service, Timeout: time.Second})
+
+			result := checker.Check(context.Background())
+			if result.Status != testCase.expectedStatus {
+				t.Fatalf("unexpected status: %s, expected: %s, error: %s",
+					result.Status, testCase.expectedStatus, result.Error)
+			}
+
+			if checker.IsHealed(context.Background()) != result.IsHealthy() {
+				t.Fatalf("IsHealed doesn't match result of Check")
+			}
+		})
+	}
+}
+
+func TestCheckerHealthServiceNotImplemented(t *testing.T) {
+	conn := startBufconnServer(t, nil)
+
+	result := NewChecker("upstream", conn, nil).Check(context.Background())
+	if result.Status != healthcheck.CheckStatusFail {
+		t.Fatalf("unexpected status: %s", result.Status)
+	}
+
+	if result.Error != ErrHealthServiceNotImplemented.Error() {
+		t.Fatalf("unexpected error: %s", result.Error)
+	}
+}
+
+func TestCheckerWatch(t *testing.T) {
+	healthServer := health.NewServer()
+	healthServer.SetServingStatus("wallet", healthpb.HealthCheckResponse_SERVING)
+
+	conn := startBufconnServer(t, healthServer)
+	checker := NewChecker("upstream", conn, &Options{Service: "wallet", Timeout: time.Second})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	checker.StartWatch(ctx)
+
+	waitCachedStatus(t, checker, healthcheck.CheckStatusPass)
+
+	healthServer.SetServingStatus("wallet", healthpb.HealthCheckResponse_NOT_SERVING)
+
+	waitCachedStatus(t, checker, healthcheck.CheckStatusFail)
+
+	// watching stopped by caller context, checker calls Check function of health service again
+	cancel()
+	checker.Close()
+
+	result := checker.Check(context.Background())
+	if _, isCached := result.Observed[ObservedCachedAtKey]; isCached {
+		t.Fatalf("cached status reported after stop of watching")
+	}
+
+	if result.Status != healthcheck.CheckStatusFail {
+		t.Fatalf("unexpected status after stop of watching: %s", result.Status)
+	}
+}
+
+func waitCachedStatus(t *testing.T, checker *checker, expectedStatus healthcheck.CheckStatus) {
+	t.Helper()
+
+	deadline := time.Now().Add(5 * time.Second)
+
+	for time.Now().Before(deadline) {
+		result := checker.Check(context.Background())
+
+		_, isCached := result.Observed[ObservedCachedAtKey]
+		if isCached && result.Status == expectedStatus {
+			return
+		}
+
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	t.Fatalf("cached status %s not received from watch stream", expectedStatus)
+}
@@ -0,0 +1,79 @@
+/*
+ *
+ *
+ * MIT NON-AI License
+ *
+ * Copyright (c) 2022-2024 Aleksei Kotelnikov(gudron2s@gmail.com)
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy of the software and associated documentation files (the "Software"),
+ * to deal in the Software without restriction, including without limitation the rights to use, copy, modify, merge, publish, distribute, sublicense,
+ * and/or sell copies of the Software, and to permit persons to whom the Software is furnished to do so, subject to the following conditions.
+ *
+ * The above copyright notice and this permission notice shall be included in all copies or substantial portions of the Software.
+ *
+ * In addition, the following restrictions apply:
+ *
+ * 1. The Software and any modifications made to it may not be used for the purpose of training or improving machine learning algorithms,
+ * including but not limited to artificial intelligence, natural language processing, or data mining. This condition applies to any derivatives,
+ * modifications, or updates based on the Software code. Any usage of the Software in an AI-training dataset is considered a breach of this License.
+ *
+ * 2. The Software may not be included in any dataset used for training or improving machine learning algorithms,
+ * including but not limited to artificial intelligence, natural language processing, or data mining.
+ *
+ * 3. Any person or organization found to be in violation of these restrictions will be subject to legal action and may be held liable
+ * for any damages resulting from such use.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM,
+ * DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE
+ * OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+ *
+ */
+
+package healthcheck
+
+import (
+	"log/slog"
+)
+
+// historyDumper - dumps last results of check unit to log on check unit transition to failed status,
+// so evidence of liveness failure stays in logs after pod restart
+type historyDumper struct {
+	l *slog.Logger
+
+	limit       int
+	historyFunc func(checkName string, limit int) *ProbeHistory
+}
+
+// OnCheckResult - implementation of checkResultObserver interface
+func (d *historyDumper) OnCheckResult(probeName string, prevResult, result *CheckResult) {
+	if result.IsHealthy() || (prevResult != nil && !prevResult.IsHealthy()) {
+		return
+	}
+
+	probeHistory := d.historyFunc(result.Name, d.limit)
+
+	for _, checkHistory := range probeHistory.Checks {
+		for _, entry := range checkHistory.Results {
+			d.l.Warn("healthcheck unit history entry",
+				slog.String(ProbeTypeTag, probeName),
+				slog.String(UnitNameTag, checkHistory.Name),
+				slog.Time(HistoryTimestampTag, entry.Timestamp),
+				slog.String(StatusTag, string(entry.Status)),
+				slog.Duration(DurationTag, entry.Duration),
+				slog.String(FailureReasonTag, entry.Error))
+		}
+	}
+}
+
+func newHistoryDumper(logger *slog.Logger,
+	limit uint,
+	historyFunc func(checkName string, limit int) *ProbeHistory,
+) *historyDumper {
+	return &historyDumper{
+		l: logger,
+
+		limit:       int(limit),
+		historyFunc: historyFunc,
+	}
+}
@@ -0,0 +1,129 @@
+/*
+ *
+ *
+ * MIT NON-AI License
+ *
+ * Copyright (c) 2022-2024 Aleksei Kotelnikov(gudron2s@gmail.com)
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy of the software and associated documentation files (the "Software"),
+ * to deal in the Software without restriction, including without limitation the rights to use, copy, modify, merge, publish, distribute, sublicense,
+ * and/or sell copies of the Software, and to permit persons to whom the Software is furnished to do so, subject to the following conditions.
+ *
+ * The above copyright notice and this permission notice shall be included in all copies or substantial portions of the Software.
+ *
+ * In addition, the following restrictions apply:
+ *
+ * 1. The Software and any modifications made to it may not be used for the purpose of training or improving machine learning algorithms,
+ * including but not limited to artificial intelligence, natural language processing, or data mining. This condition applies to any derivatives,
+ * modifications, or updates based on the Software code. Any usage of the Software in an AI-training dataset is considered a breach of this License.
+ *
+ * 2. The Software may not be included in any dataset used for training or improving machine learning algorithms,
+ * including but not limited to artificial intelligence, natural language processing, or data mining.
+ *
+ * 3. Any person or organization found to be in violation of these restrictions will be subject to legal action and may be held liable
+ * for any damages resulting from such use.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM,
+ * DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE
+ * OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+ *
+ */
+
+package healthcheck
+
+import (
+	"crypto/subtle"
+	"log/slog"
+	"net"
+	"net/http"
+	"strings"
+)
+
+const (
+	bearerAuthPrefix = "Bearer "
+)
+
+// accessPolicy - decides which requests allowed to receive verbose healthcheck report.
+// Request allowed if client address contains in one of allowed networks or request carries configured bearer token
+type accessPolicy struct {
+	bearerToken     []byte
+	allowedNetworks []*net.IPNet
+}
+
+// Authorize - returns http.StatusOK if request allowed to receive verbose report,
+// http.StatusUnauthorized if request has no credentials, http.StatusForbidden if credentials are wrong
+func (p *accessPolicy) Authorize(httpReq *http.Request) int {
+	if p.isAllowedAddress(httpReq.RemoteAddr) {
+		return http.StatusOK
+	}
+
+	authHeader := httpReq.Header.Get("Authorization")
+	if authHeader == "" {
+		return http.StatusUnauthorized
+	}
+
+	if len(p.bearerToken) == 0 || !strings.HasPrefix(authHeader, bearerAuthPrefix) {
+		return http.StatusForbidden
+	}
+
+	token := []byte(strings.TrimPrefix(authHeader, bearerAuthPrefix))
+	if subtle.ConstantTimeCompare(token, p.bearerToken) != 1 {
+		return http.StatusForbidden
+	}
+
+	return http.StatusOK
+}
+
+func (p *accessPolicy) isAllowedAddress(remoteAddr string) bool {
+	if len(p.allowedNetworks) == 0 {
+		return false
+	}
+
+	host, _, err := net.SplitHostPort(remoteAddr)
+	if err != nil {
+		host = remoteAddr
+	}
+
+	clientIP := net.ParseIP(host)
+	if clientIP == nil {
+		return false
+	}
+
+	for _, network := range p.allowedNetworks {
+		if network.Contains(clientIP) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func newAccessPolicy(logger *slog.Logger,
+	cfgSvc verboseConfigService,
+) *accessPolicy {
+	cidrList := cfgSvc.GetVerboseAllowedCIDRs()
+	allowedNetworks := make([]*net.IPNet, 0, len(cidrList))
+
+	for _, cidr := range cidrList {
+		cidr = strings.TrimSpace(cidr)
+		if cidr == "" {
+			continue
+		}
+
+		_, network, err := net.ParseCIDR(cidr)
+		if err != nil {
+			logger.Error("unable to parse verbose allowed cidr, skipped", slog.Any(ErrorTag, err),
+				slog.String(AllowedCIDRTag, cidr))
+
+			continue
+		}
+
+		allowedNetworks = append(allowedNetworks, network)
+	}
+
+	return &accessPolicy{
+		bearerToken:     []byte(cfgSvc.GetVerboseBearerToken()),
+		allowedNetworks: allowedNetworks,
+	}
+}
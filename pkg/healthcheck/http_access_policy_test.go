@@ -0,0 +1,311 @@
+/*
+ *
+ *
+ * MIT NON-AI License
+ *
+ * Copyright (c) 2022-2024 Aleksei Kotelnikov(gudron2s@gmail.com)
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy of the software and associated documentation files (the "Software"),
+ * to deal in the Software without restriction, including without limitation the rights to use, copy, modify, merge, publish, distribute, sublicense,
+ * and/or sell copies of the Software, and to permit persons to whom the Software is furnished to do so, subject to the following conditions.
+ *
+ * The above copyright notice and this permission notice shall be included in all copies or substantial portions of the Software.
+ *
+ * In addition, the following restrictions apply:
+ *
+ * 1. The Software and any modifications made to it may not be used for the purpose of training or improving machine learning algorithms,
+ * including but not limited to artificial intelligence, natural language processing, or data mining. This condition applies to any derivatives,
+ * modifications, or updates based on the Software code. Any usage of the Software in an AI-training dataset is considered a breach of this License.
+ *
+ * 2. The Software may not be included in any dataset used for training or improving machine learning algorithms,
+ * including but not limited to artificial intelligence, natural language processing, or data mining.
+ *
+ * 3. Any person or organization found to be in violation of these restrictions will be subject to legal action and may be held liable
+ * for any damages resulting from such use.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM,
+ * DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE
+ * OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+ *
+ */
+package healthcheck
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+type fakeVerboseConfig struct {
+	bearerToken  string
+	allowedCIDRs []string
+}
+
+func (c *fakeVerboseConfig) GetVerboseBearerToken() string {
+	return c.bearerToken
+}
+
+func (c *fakeVerboseConfig) GetVerboseAllowedCIDRs() []string {
+	return c.allowedCIDRs
+}
+
+// fakeProbeUnit - named check unit with switchable status, counts own executions
+type fakeProbeUnit struct {
+	name         string
+	dependencies []string
+
+	calls atomic.Int64
+
+	status CheckStatus
+	mu     sync.Mutex
+}
+
+func (u *fakeProbeUnit) GetName() string {
+	return u.name
+}
+
+func (u *fakeProbeUnit) GetDependencies() []string {
+	return u.dependencies
+}
+
+func (u *fakeProbeUnit) SetStatus(status CheckStatus) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	u.status = status
+}
+
+func (u *fakeProbeUnit) IsHealed(ctx context.Context) bool {
+	return u.Check(ctx).IsHealthy()
+}
+
+func (u *fakeProbeUnit) Check(_ context.Context) *CheckResult {
+	u.calls.Add(1)
+
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	result := NewCheckResult(u.name)
+	if u.status != "" {
+		result.Status = u.status
+	}
+
+	if result.Status != CheckStatusPass {
+		result.Error = u.name + " " + string(result.Status)
+	}
+
+	return result
+}
+
+func newFakeProbeUnit(name string, status CheckStatus, dependencies ...string) *fakeProbeUnit {
+	return &fakeProbeUnit{
+		name:         name,
+		dependencies: dependencies,
+
+		calls: atomic.Int64{},
+
+		status: status,
+		mu:     sync.Mutex{},
+	}
+}
+
+func TestAccessPolicy_Authorize(t *testing.T) {
+	policy := newAccessPolicy(newDiscardLogger(), &fakeVerboseConfig{
+		bearerToken:  "s3cr3t-token",
+		allowedCIDRs: []string{"10.0.0.0/8", " ", "not-a-cidr", "fd00::/8"},
+	})
+
+	testCases := []struct {
+		name               string
+		remoteAddr         string
+		headers            map[string]string
+		expectedStatusCode int
+	}{
+		{
+			name:               "missing bearer token",
+			remoteAddr:         "192.0.2.10:34567",
+			headers:            nil,
+			expectedStatusCode: http.StatusUnauthorized,
+		},
+		{
+			name:               "wrong bearer token",
+			remoteAddr:         "192.0.2.10:34567",
+			headers:            map[string]string{"Authorization": "Bearer wrong"},
+			expectedStatusCode: http.StatusForbidden,
+		},
+		{
+			name:               "bearer token of same length with different last byte",
+			remoteAddr:         "192.0.2.10:34567",
+			headers:            map[string]string{"Authorization": "Bearer s3cr3t-tokem"},
+			expectedStatusCode: http.StatusForbidden,
+		},
+		{
+			name:               "prefix of bearer token",
+			remoteAddr:         "192.0.2.10:34567",
+			headers:            map[string]string{"Authorization": "Bearer s3cr3t"},
+			expectedStatusCode: http.StatusForbidden,
+		},
+		{
+			name:               "token with other auth scheme",
+			remoteAddr:         "192.0.2.10:34567",
+			headers:            map[string]string{"Authorization": "Basic s3cr3t-token"},
+			expectedStatusCode: http.StatusForbidden,
+		},
+		{
+			name:               "matched bearer token",
+			remoteAddr:         "192.0.2.10:34567",
+			headers:            map[string]string{"Authorization": "Bearer s3cr3t-token"},
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			name:               "allowed ipv4 network",
+			remoteAddr:         "10.1.2.3:34567",
+			headers:            nil,
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			name:               "allowed ipv6 network",
+			remoteAddr:         "[fd00::1]:34567",
+			headers:            nil,
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			name:               "denied network",
+			remoteAddr:         "11.1.2.3:34567",
+			headers:            nil,
+			expectedStatusCode: http.StatusUnauthorized,
+		},
+		{
+			name:       "x-forwarded-for header ignored",
+			remoteAddr: "192.0.2.10:34567",
+			headers: map[string]string{
+				"X-Forwarded-For": "10.1.2.3",
+				"X-Real-Ip":       "10.1.2.3",
+			},
+			expectedStatusCode: http.StatusUnauthorized,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			httpReq := httptest.NewRequest(http.MethodGet, "/liveness?verbose", nil)
+			httpReq.RemoteAddr = testCase.remoteAddr
+
+			for key, value := range testCase.headers {
+				httpReq.Header.Set(key, value)
+			}
+
+			statusCode := policy.Authorize(httpReq)
+			if statusCode != testCase.expectedStatusCode {
+				t.Fatalf("expected status code %d, got %d", testCase.expectedStatusCode, statusCode)
+			}
+		})
+	}
+}
+
+func TestAccessPolicy_Authorize_WithoutCredentials(t *testing.T) {
+	policy := newAccessPolicy(newDiscardLogger(), &fakeVerboseConfig{
+		bearerToken:  "",
+		allowedCIDRs: nil,
+	})
+
+	httpReq := httptest.NewRequest(http.MethodGet, "/liveness?verbose", nil)
+	httpReq.Header.Set("Authorization", "Bearer ")
+
+	statusCode := policy.Authorize(httpReq)
+	if statusCode != http.StatusForbidden {
+		t.Fatalf("expected status code %d, got %d", http.StatusForbidden, statusCode)
+	}
+}
+
+func TestHTTPHandler_VerboseAccess(t *testing.T) {
+	testCases := []struct {
+		name               string
+		target             string
+		authHeader         string
+		expectedStatusCode int
+		expectedBody       string
+		expectedCalls      int64
+	}{
+		{
+			name:               "anonymous request gets minimal body",
+			target:             "/liveness",
+			authHeader:         "",
+			expectedStatusCode: http.StatusOK,
+			expectedBody:       AppHealthyMessage,
+			expectedCalls:      1,
+		},
+		{
+			name:               "unauthorized verbose request",
+			target:             "/liveness?verbose",
+			authHeader:         "",
+			expectedStatusCode: http.StatusUnauthorized,
+			expectedBody:       http.StatusText(http.StatusUnauthorized),
+			expectedCalls:      0,
+		},
+		{
+			name:               "forbidden verbose request",
+			target:             "/liveness?verbose=true",
+			authHeader:         "Bearer wrong",
+			expectedStatusCode: http.StatusForbidden,
+			expectedBody:       http.StatusText(http.StatusForbidden),
+			expectedCalls:      0,
+		},
+		{
+			name:               "disabled verbose mode not authorized",
+			target:             "/liveness?verbose=false",
+			authHeader:         "",
+			expectedStatusCode: http.StatusOK,
+			expectedBody:       AppHealthyMessage,
+			expectedCalls:      1,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			unit := newFakeProbeUnit("database", CheckStatusPass)
+
+			checker := newProbeChecker(ProbeNameLiveness, newHealthTracer(), &checkUnitParams{HistorySize: 1}) //nolint:exhaustruct // defaults
+			if err := checker.AddUnit(unit); err != nil {
+				t.Fatalf("unable to add unit: %s", err)
+			}
+
+			policy := newAccessPolicy(newDiscardLogger(), &fakeVerboseConfig{
+				bearerToken:  "s3cr3t-token",
+				allowedCIDRs: nil,
+			})
+
+			handler := newHTTPHandler(newDiscardLogger(), checker, policy)
+
+			httpReq := httptest.NewRequest(http.MethodGet, testCase.target, nil)
+			if testCase.authHeader != "" {
+				httpReq.Header.Set("Authorization", testCase.authHeader)
+			}
+
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, httpReq)
+
+			if recorder.Code != testCase.expectedStatusCode {
+				t.Fatalf("expected status code %d, got %d", testCase.expectedStatusCode, recorder.Code)
+			}
+
+			body, err := io.ReadAll(recorder.Body)
+			if err != nil {
+				t.Fatalf("unable to read body: %s", err)
+			}
+
+			if string(body) != testCase.expectedBody {
+				t.Fatalf("expected body %q, got %q", testCase.expectedBody, string(body))
+			}
+
+			if unit.calls.Load() != testCase.expectedCalls {
+				t.Fatalf("expected %d executions of unit, got %d", testCase.expectedCalls, unit.calls.Load())
+			}
+		})
+	}
+}
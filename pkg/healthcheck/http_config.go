@@ -33,16 +33,25 @@
 package healthcheck
 
 import (
+	"errors"
 	"fmt"
 	"time"
 )
 
+var (
+	ErrFlappingHoldStatusInvalid = errors.New("flapping hold status must be pass, warn, fail or empty")
+)
+
 type LivenessHTTPConfig struct {
-	HealthCheckLivenessHTTPPath         string        `envconfig:"HEALTH_CHECK_LIVENESS_HTTP_PATH" default:"/liveness"`
-	HealthCheckLivenessHTTPPort         uint          `envconfig:"HEALTH_CHECK_LIVENESS_HTTP_PORT" default:"8200"`
-	HealthCheckLivenessHTTPReadTimeout  time.Duration `envconfig:"HEALTH_CHECK_LIVENESS_HTTP_READ_TIMEOUT" default:"5s"`
-	HealthCheckLivenessHTTPWriteTimeout time.Duration `envconfig:"HEALTH_CHECK_LIVENESS_HTTP_WRITE_TIMEOUT" default:"10s"`
-	HealthCheckLivenessEnabled          bool          `envconfig:"HEALTH_CHECK_LIVENESS_ENABLED" default:"true"`
+	HealthCheckLivenessHTTPPath            string        `envconfig:"HEALTH_CHECK_LIVENESS_HTTP_PATH" default:"/liveness"`
+	HealthCheckLivenessHTTPPort            uint          `envconfig:"HEALTH_CHECK_LIVENESS_HTTP_PORT" default:"8200"`
+	HealthCheckLivenessHTTPReadTimeout     time.Duration `envconfig:"HEALTH_CHECK_LIVENESS_HTTP_READ_TIMEOUT" default:"5s"`
+	HealthCheckLivenessHTTPWriteTimeout    time.Duration `envconfig:"HEALTH_CHECK_LIVENESS_HTTP_WRITE_TIMEOUT" default:"10s"`
+	HealthCheckLivenessEnabled             bool          `envconfig:"HEALTH_CHECK_LIVENESS_ENABLED" default:"true"`
+	HealthCheckLivenessHTTPTLSEnabled      bool          `envconfig:"HEALTH_CHECK_LIVENESS_HTTP_TLS_ENABLED" default:"false"`
+	HealthCheckLivenessHTTPTLSCertPath     string        `envconfig:"HEALTH_CHECK_LIVENESS_HTTP_TLS_CERT_PATH" default:""`
+	HealthCheckLivenessHTTPTLSKeyPath      string        `envconfig:"HEALTH_CHECK_LIVENESS_HTTP_TLS_KEY_PATH" default:""`
+	HealthCheckLivenessHTTPTLSClientCAPath string        `envconfig:"HEALTH_CHECK_LIVENESS_HTTP_TLS_CLIENT_CA_PATH" default:""`
 }
 
 func (c *LivenessHTTPConfig) IsLivenessProbeEnable() bool {
@@ -69,12 +78,44 @@ func (c *LivenessHTTPConfig) GetLivenessProbeListenPort() uint {
 	return c.HealthCheckLivenessHTTPPort
 }
 
+func (c *LivenessHTTPConfig) IsLivenessProbeTLSEnable() bool {
+	return c != nil && c.HealthCheckLivenessHTTPTLSEnabled
+}
+
+func (c *LivenessHTTPConfig) GetLivenessProbeTLSCertPath() string {
+	if c == nil {
+		return ""
+	}
+
+	return c.HealthCheckLivenessHTTPTLSCertPath
+}
+
+func (c *LivenessHTTPConfig) GetLivenessProbeTLSKeyPath() string {
+	if c == nil {
+		return ""
+	}
+
+	return c.HealthCheckLivenessHTTPTLSKeyPath
+}
+
+func (c *LivenessHTTPConfig) GetLivenessProbeTLSClientCAPath() string {
+	if c == nil {
+		return ""
+	}
+
+	return c.HealthCheckLivenessHTTPTLSClientCAPath
+}
+
 type ReadinessHTTPConfig struct {
-	HealthCheckReadinessHTTPPath         string        `envconfig:"HEALTH_CHECK_READINESS_HTTP_PATH" default:"/rediness"`
-	HealthCheckReadinessHTTPPort         uint          `envconfig:"HEALTH_CHECK_READINESS_HTTP_PORT" default:"8201"`
-	HealthCheckReadinessHTTPReadTimeout  time.Duration `envconfig:"HEALTH_CHECK_READINESS_HTTP_READ_TIMEOUT" default:"5s"`
-	HealthCheckReadinessHTTPWriteTimeout time.Duration `envconfig:"HEALTH_CHECK_READINESS_HTTP_WRITE_TIMEOUT" default:"10s"`
-	HealthCheckReadinessEnabled          bool          `envconfig:"HEALTH_CHECK_READINESS_ENABLED" default:"true"`
+	HealthCheckReadinessHTTPPath            string        `envconfig:"HEALTH_CHECK_READINESS_HTTP_PATH" default:"/rediness"`
+	HealthCheckReadinessHTTPPort            uint          `envconfig:"HEALTH_CHECK_READINESS_HTTP_PORT" default:"8201"`
+	HealthCheckReadinessHTTPReadTimeout     time.Duration `envconfig:"HEALTH_CHECK_READINESS_HTTP_READ_TIMEOUT" default:"5s"`
+	HealthCheckReadinessHTTPWriteTimeout    time.Duration `envconfig:"HEALTH_CHECK_READINESS_HTTP_WRITE_TIMEOUT" default:"10s"`
+	HealthCheckReadinessEnabled             bool          `envconfig:"HEALTH_CHECK_READINESS_ENABLED" default:"true"`
+	HealthCheckReadinessHTTPTLSEnabled      bool          `envconfig:"HEALTH_CHECK_READINESS_HTTP_TLS_ENABLED" default:"false"`
+	HealthCheckReadinessHTTPTLSCertPath     string        `envconfig:"HEALTH_CHECK_READINESS_HTTP_TLS_CERT_PATH" default:""`
+	HealthCheckReadinessHTTPTLSKeyPath      string        `envconfig:"HEALTH_CHECK_READINESS_HTTP_TLS_KEY_PATH" default:""`
+	HealthCheckReadinessHTTPTLSClientCAPath string        `envconfig:"HEALTH_CHECK_READINESS_HTTP_TLS_CLIENT_CA_PATH" default:""`
 }
 
 func (c *ReadinessHTTPConfig) IsReadinessProbeEnable() bool {
@@ -101,12 +142,44 @@ func (c *ReadinessHTTPConfig) GetReadinessProbeListenPort() uint {
 	return c.HealthCheckReadinessHTTPPort
 }
 
+func (c *ReadinessHTTPConfig) IsReadinessProbeTLSEnable() bool {
+	return c != nil && c.HealthCheckReadinessHTTPTLSEnabled
+}
+
+func (c *ReadinessHTTPConfig) GetReadinessProbeTLSCertPath() string {
+	if c == nil {
+		return ""
+	}
+
+	return c.HealthCheckReadinessHTTPTLSCertPath
+}
+
+func (c *ReadinessHTTPConfig) GetReadinessProbeTLSKeyPath() string {
+	if c == nil {
+		return ""
+	}
+
+	return c.HealthCheckReadinessHTTPTLSKeyPath
+}
+
+func (c *ReadinessHTTPConfig) GetReadinessProbeTLSClientCAPath() string {
+	if c == nil {
+		return ""
+	}
+
+	return c.HealthCheckReadinessHTTPTLSClientCAPath
+}
+
 type StartupHTTPConfig struct {
-	HealthCheckStartupHTTPPath         string        `envconfig:"HEALTH_CHECK_STARTUP_HTTP_PATH" default:"/startup"`
-	HealthCheckStartupHTTPPort         uint          `envconfig:"HEALTH_CHECK_STARTUP_HTTP_PORT" default:"8202"`
-	HealthCheckStartupHTTPReadTimeout  time.Duration `envconfig:"HEALTH_CHECK_STARTUP_HTTP_READ_TIMEOUT" default:"5s"`
-	HealthCheckStartupHTTPWriteTimeout time.Duration `envconfig:"HEALTH_CHECK_STARTUP_HTTP_WRITE_TIMEOUT" default:"10s"`
-	HealthCheckStartupEnabled          bool          `envconfig:"HEALTH_CHECK_STARTUP_ENABLED" default:"true"`
+	HealthCheckStartupHTTPPath            string        `envconfig:"HEALTH_CHECK_STARTUP_HTTP_PATH" default:"/startup"`
+	HealthCheckStartupHTTPPort            uint          `envconfig:"HEALTH_CHECK_STARTUP_HTTP_PORT" default:"8202"`
+	HealthCheckStartupHTTPReadTimeout     time.Duration `envconfig:"HEALTH_CHECK_STARTUP_HTTP_READ_TIMEOUT" default:"5s"`
+	HealthCheckStartupHTTPWriteTimeout    time.Duration `envconfig:"HEALTH_CHECK_STARTUP_HTTP_WRITE_TIMEOUT" default:"10s"`
+	HealthCheckStartupEnabled             bool          `envconfig:"HEALTH_CHECK_STARTUP_ENABLED" default:"true"`
+	HealthCheckStartupHTTPTLSEnabled      bool          `envconfig:"HEALTH_CHECK_STARTUP_HTTP_TLS_ENABLED" default:"false"`
+	HealthCheckStartupHTTPTLSCertPath     string        `envconfig:"HEALTH_CHECK_STARTUP_HTTP_TLS_CERT_PATH" default:""`
+	HealthCheckStartupHTTPTLSKeyPath      string        `envconfig:"HEALTH_CHECK_STARTUP_HTTP_TLS_KEY_PATH" default:""`
+	HealthCheckStartupHTTPTLSClientCAPath string        `envconfig:"HEALTH_CHECK_STARTUP_HTTP_TLS_CLIENT_CA_PATH" default:""`
 }
 
 func (c *ReadinessHTTPConfig) IsStartupProbeEnable() bool {
@@ -133,10 +206,277 @@ func (c *StartupHTTPConfig) GetStartupProbeListenPort() uint {
 	return c.HealthCheckStartupHTTPPort
 }
 
+func (c *StartupHTTPConfig) IsStartupProbeTLSEnable() bool {
+	return c != nil && c.HealthCheckStartupHTTPTLSEnabled
+}
+
+func (c *StartupHTTPConfig) GetStartupProbeTLSCertPath() string {
+	if c == nil {
+		return ""
+	}
+
+	return c.HealthCheckStartupHTTPTLSCertPath
+}
+
+func (c *StartupHTTPConfig) GetStartupProbeTLSKeyPath() string {
+	if c == nil {
+		return ""
+	}
+
+	return c.HealthCheckStartupHTTPTLSKeyPath
+}
+
+func (c *StartupHTTPConfig) GetStartupProbeTLSClientCAPath() string {
+	if c == nil {
+		return ""
+	}
+
+	return c.HealthCheckStartupHTTPTLSClientCAPath
+}
+
+type VerboseHTTPConfig struct {
+	HealthCheckVerboseBearerToken  string   `envconfig:"HEALTH_CHECK_VERBOSE_BEARER_TOKEN" default:""`
+	HealthCheckVerboseAllowedCIDRs []string `envconfig:"HEALTH_CHECK_VERBOSE_ALLOWED_CIDRS" default:""`
+}
+
+func (c *VerboseHTTPConfig) GetVerboseBearerToken() string {
+	if c == nil {
+		return ""
+	}
+
+	return c.HealthCheckVerboseBearerToken
+}
+
+func (c *VerboseHTTPConfig) GetVerboseAllowedCIDRs() []string {
+	if c == nil {
+		return nil
+	}
+
+	return c.HealthCheckVerboseAllowedCIDRs
+}
+
+const defaultMetricsHTTPPath = "/metrics"
+
+type MetricsHTTPConfig struct {
+	HealthCheckMetricsHTTPPath string `envconfig:"HEALTH_CHECK_METRICS_HTTP_PATH" default:"/metrics"`
+	HealthCheckMetricsEnabled  bool   `envconfig:"HEALTH_CHECK_METRICS_ENABLED" default:"false"`
+}
+
+func (c *MetricsHTTPConfig) IsMetricsEnable() bool {
+	return c != nil && c.HealthCheckMetricsEnabled
+}
+
+func (c *MetricsHTTPConfig) GetMetricsRequestPath() string {
+	if c == nil {
+		return defaultMetricsHTTPPath
+	}
+
+	return c.HealthCheckMetricsHTTPPath
+}
+
+const defaultTransitionsLogRepeatInterval = time.Minute
+
+type TransitionsLogConfig struct {
+	HealthCheckLogRepeatInterval time.Duration `envconfig:"HEALTH_CHECK_LOG_REPEAT_INTERVAL" default:"1m"`
+}
+
+func (c *TransitionsLogConfig) GetTransitionsLogRepeatInterval() time.Duration {
+	if c == nil {
+		return defaultTransitionsLogRepeatInterval
+	}
+
+	return c.HealthCheckLogRepeatInterval
+}
+
+const (
+	defaultEventsStreamHTTPPath       = "/events"
+	defaultEventsStreamMaxConnections = 16
+)
+
+type EventsStreamHTTPConfig struct {
+	HealthCheckEventsStreamHTTPPath          string        `envconfig:"HEALTH_CHECK_EVENTS_STREAM_HTTP_PATH" default:"/events"`
+	HealthCheckEventsStreamHeartbeatInterval time.Duration `envconfig:"HEALTH_CHECK_EVENTS_STREAM_HEARTBEAT_INTERVAL" default:"15s"`
+	HealthCheckEventsStreamMaxConnections    uint          `envconfig:"HEALTH_CHECK_EVENTS_STREAM_MAX_CONNECTIONS" default:"16"`
+	HealthCheckEventsStreamEnabled           bool          `envconfig:"HEALTH_CHECK_EVENTS_STREAM_ENABLED" default:"false"`
+}
+
+func (c *EventsStreamHTTPConfig) IsEventsStreamEnable() bool {
+	return c != nil && c.HealthCheckEventsStreamEnabled
+}
+
+func (c *EventsStreamHTTPConfig) GetEventsStreamRequestPath() string {
+	if c == nil {
+		return defaultEventsStreamHTTPPath
+	}
+
+	return c.HealthCheckEventsStreamHTTPPath
+}
+
+func (c *EventsStreamHTTPConfig) GetEventsStreamHeartbeatInterval() time.Duration {
+	if c == nil {
+		return defaultEventsStreamHeartbeatInterval
+	}
+
+	return c.HealthCheckEventsStreamHeartbeatInterval
+}
+
+func (c *EventsStreamHTTPConfig) GetEventsStreamMaxConnections() uint {
+	if c == nil {
+		return defaultEventsStreamMaxConnections
+	}
+
+	return c.HealthCheckEventsStreamMaxConnections
+}
+
+const defaultStatusPageHTTPPath = "/status"
+
+type StatusPageHTTPConfig struct {
+	HealthCheckStatusPageHTTPPath string `envconfig:"HEALTH_CHECK_STATUS_PAGE_HTTP_PATH" default:"/status"`
+	HealthCheckStatusPageEnabled  bool   `envconfig:"HEALTH_CHECK_STATUS_PAGE_ENABLED" default:"false"`
+}
+
+func (c *StatusPageHTTPConfig) IsStatusPageEnable() bool {
+	return c != nil && c.HealthCheckStatusPageEnabled
+}
+
+func (c *StatusPageHTTPConfig) GetStatusPageRequestPath() string {
+	if c == nil {
+		return defaultStatusPageHTTPPath
+	}
+
+	return c.HealthCheckStatusPageHTTPPath
+}
+
+const (
+	defaultHistoryHTTPPath = "/history"
+	defaultHistorySize     = 60
+	defaultHistoryDumpSize = 10
+)
+
+type HistoryHTTPConfig struct {
+	HealthCheckHistoryHTTPPath              string `envconfig:"HEALTH_CHECK_HISTORY_HTTP_PATH" default:"/history"`
+	HealthCheckHistorySize                  uint   `envconfig:"HEALTH_CHECK_HISTORY_SIZE" default:"60"`
+	HealthCheckHistoryDumpSize              uint   `envconfig:"HEALTH_CHECK_HISTORY_DUMP_SIZE" default:"10"`
+	HealthCheckHistoryEnabled               bool   `envconfig:"HEALTH_CHECK_HISTORY_ENABLED" default:"false"`
+	HealthCheckHistoryDumpOnLivenessFailure bool   `envconfig:"HEALTH_CHECK_HISTORY_DUMP_ON_LIVENESS_FAILURE" default:"false"`
+}
+
+func (c *HistoryHTTPConfig) IsHistoryEnable() bool {
+	return c != nil && c.HealthCheckHistoryEnabled
+}
+
+func (c *HistoryHTTPConfig) GetHistoryRequestPath() string {
+	if c == nil {
+		return defaultHistoryHTTPPath
+	}
+
+	return c.HealthCheckHistoryHTTPPath
+}
+
+func (c *HistoryHTTPConfig) GetHistorySize() uint {
+	if c == nil {
+		return defaultHistorySize
+	}
+
+	return c.HealthCheckHistorySize
+}
+
+func (c *HistoryHTTPConfig) IsHistoryDumpOnLivenessFailureEnable() bool {
+	return c != nil && c.HealthCheckHistoryDumpOnLivenessFailure
+}
+
+func (c *HistoryHTTPConfig) GetHistoryDumpSize() uint {
+	if c == nil {
+		return defaultHistoryDumpSize
+	}
+
+	return c.HealthCheckHistoryDumpSize
+}
+
+const (
+	defaultFlappingWindowSize    = 21
+	defaultFlappingHighThreshold = 50
+	defaultFlappingLowThreshold  = 25
+)
+
+type FlappingConfig struct {
+	HealthCheckFlappingHoldStatus    string  `envconfig:"HEALTH_CHECK_FLAPPING_HOLD_STATUS" default:""`
+	HealthCheckFlappingWindowSize    uint    `envconfig:"HEALTH_CHECK_FLAPPING_WINDOW_SIZE" default:"21"`
+	HealthCheckFlappingHighThreshold float64 `envconfig:"HEALTH_CHECK_FLAPPING_HIGH_THRESHOLD" default:"50"`
+	HealthCheckFlappingLowThreshold  float64 `envconfig:"HEALTH_CHECK_FLAPPING_LOW_THRESHOLD" default:"25"`
+	HealthCheckFlappingEnabled       bool    `envconfig:"HEALTH_CHECK_FLAPPING_ENABLED" default:"false"`
+}
+
+func (c *FlappingConfig) IsFlappingDetectionEnable() bool {
+	return c != nil && c.HealthCheckFlappingEnabled
+}
+
+func (c *FlappingConfig) GetFlappingWindowSize() uint {
+	if c == nil {
+		return defaultFlappingWindowSize
+	}
+
+	return c.HealthCheckFlappingWindowSize
+}
+
+func (c *FlappingConfig) GetFlappingHighThreshold() float64 {
+	if c == nil {
+		return defaultFlappingHighThreshold
+	}
+
+	return c.HealthCheckFlappingHighThreshold
+}
+
+func (c *FlappingConfig) GetFlappingLowThreshold() float64 {
+	if c == nil {
+		return defaultFlappingLowThreshold
+	}
+
+	return c.HealthCheckFlappingLowThreshold
+}
+
+// GetFlappingHoldStatus - status of flapping check units - pass, warn or fail.
+// Empty value means status is not overridden
+func (c *FlappingConfig) GetFlappingHoldStatus() CheckStatus {
+	if c == nil {
+		return ""
+	}
+
+	switch status := CheckStatus(c.HealthCheckFlappingHoldStatus); status {
+	case CheckStatusPass, CheckStatusWarn, CheckStatusFail:
+		return status
+	default:
+		return ""
+	}
+}
+
+// validate - check value of flapping hold status, silent fallback to not overridden status hides config typo
+func (c *FlappingConfig) validate() error {
+	if c == nil || c.HealthCheckFlappingHoldStatus == "" {
+		return nil
+	}
+
+	switch CheckStatus(c.HealthCheckFlappingHoldStatus) {
+	case CheckStatusPass, CheckStatusWarn, CheckStatusFail:
+		return nil
+	default:
+		return fmt.Errorf("%w: %s", ErrFlappingHoldStatusInvalid, c.HealthCheckFlappingHoldStatus)
+	}
+}
+
+// HealthcheckHTTPConfig - config of probes http-servers. Configs of optional features can be nil,
+// getters of nil feature config return default values of feature settings
 type HealthcheckHTTPConfig struct {
 	*LivenessHTTPConfig
 	*ReadinessHTTPConfig
 	*StartupHTTPConfig
+	*VerboseHTTPConfig
+	*MetricsHTTPConfig
+	*TransitionsLogConfig
+	*EventsStreamHTTPConfig
+	*StatusPageHTTPConfig
+	*HistoryHTTPConfig
+	*FlappingConfig
 }
 
 func (c *HealthcheckHTTPConfig) GetStartupParams() *unitConfig {
@@ -145,7 +485,18 @@ func (c *HealthcheckHTTPConfig) GetStartupParams() *unitConfig {
 		HTTPReadTimeout:  c.HealthCheckStartupHTTPReadTimeout,
 		HTTPWriteTimeout: c.HealthCheckStartupHTTPWriteTimeout,
 		HTTPPath:         c.HealthCheckStartupHTTPPath,
+		TLSEnabled:       c.HealthCheckStartupHTTPTLSEnabled,
+		TLSCertPath:      c.HealthCheckStartupHTTPTLSCertPath,
+		TLSKeyPath:       c.HealthCheckStartupHTTPTLSKeyPath,
+		TLSClientCAPath:  c.HealthCheckStartupHTTPTLSClientCAPath,
 		ProbeName:        ProbeNameStartup,
+		HistorySize:      c.GetHistorySize(),
+
+		FlappingEnabled:       c.IsFlappingDetectionEnable(),
+		FlappingWindowSize:    c.GetFlappingWindowSize(),
+		FlappingHighThreshold: c.GetFlappingHighThreshold(),
+		FlappingLowThreshold:  c.GetFlappingLowThreshold(),
+		FlappingHoldStatus:    c.GetFlappingHoldStatus(),
 	}
 }
 
@@ -155,7 +506,18 @@ func (c *HealthcheckHTTPConfig) GetReadinessParams() *unitConfig {
 		HTTPReadTimeout:  c.HealthCheckReadinessHTTPReadTimeout,
 		HTTPWriteTimeout: c.HealthCheckReadinessHTTPWriteTimeout,
 		HTTPPath:         c.HealthCheckReadinessHTTPPath,
+		TLSEnabled:       c.HealthCheckReadinessHTTPTLSEnabled,
+		TLSCertPath:      c.HealthCheckReadinessHTTPTLSCertPath,
+		TLSKeyPath:       c.HealthCheckReadinessHTTPTLSKeyPath,
+		TLSClientCAPath:  c.HealthCheckReadinessHTTPTLSClientCAPath,
 		ProbeName:        ProbeNameRediness,
+		HistorySize:      c.GetHistorySize(),
+
+		FlappingEnabled:       c.IsFlappingDetectionEnable(),
+		FlappingWindowSize:    c.GetFlappingWindowSize(),
+		FlappingHighThreshold: c.GetFlappingHighThreshold(),
+		FlappingLowThreshold:  c.GetFlappingLowThreshold(),
+		FlappingHoldStatus:    c.GetFlappingHoldStatus(),
 	}
 }
 
@@ -165,12 +527,28 @@ func (c *HealthcheckHTTPConfig) GetLivenessParams() *unitConfig {
 		HTTPReadTimeout:  c.HealthCheckLivenessHTTPReadTimeout,
 		HTTPWriteTimeout: c.HealthCheckLivenessHTTPWriteTimeout,
 		HTTPPath:         c.HealthCheckLivenessHTTPPath,
+		TLSEnabled:       c.HealthCheckLivenessHTTPTLSEnabled,
+		TLSCertPath:      c.HealthCheckLivenessHTTPTLSCertPath,
+		TLSKeyPath:       c.HealthCheckLivenessHTTPTLSKeyPath,
+		TLSClientCAPath:  c.HealthCheckLivenessHTTPTLSClientCAPath,
 		ProbeName:        ProbeNameLiveness,
+		HistorySize:      c.GetHistorySize(),
+
+		FlappingEnabled:       c.IsFlappingDetectionEnable(),
+		FlappingWindowSize:    c.GetFlappingWindowSize(),
+		FlappingHighThreshold: c.GetFlappingHighThreshold(),
+		FlappingLowThreshold:  c.GetFlappingLowThreshold(),
+		FlappingHoldStatus:    c.GetFlappingHoldStatus(),
 	}
 }
 
 // Prepare variables to static configuration...
 func (c *HealthcheckHTTPConfig) Prepare() error {
+	err := c.FlappingConfig.validate()
+	if err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -178,12 +556,87 @@ func (c *HealthcheckHTTPConfig) PrepareWith(cfgSrvList ...interface{}) error {
 	return nil
 }
 
+// optionalConfig - settings of optional features. Config service can implement optional config interfaces
+// of features, default settings used for not implemented interfaces
+type optionalConfig struct {
+	tlsConfigService
+	verboseConfigService
+	metricsConfigService
+	transitionsLogConfigService
+	eventsStreamConfigService
+	statusPageConfigService
+	historyConfigService
+	flappingConfigService
+}
+
+func newOptionalConfig(cfgSvc configService) *optionalConfig {
+	// getters of nil feature configs return default settings
+	//nolint:exhaustruct // it's ok here, nil feature configs used as defaults
+	defaults := &HealthcheckHTTPConfig{}
+
+	optCfg := &optionalConfig{
+		tlsConfigService:            defaults,
+		verboseConfigService:        defaults,
+		metricsConfigService:        defaults,
+		transitionsLogConfigService: defaults,
+		eventsStreamConfigService:   defaults,
+		statusPageConfigService:     defaults,
+		historyConfigService:        defaults,
+		flappingConfigService:       defaults,
+	}
+
+	if tlsCfg, ok := cfgSvc.(tlsConfigService); ok {
+		optCfg.tlsConfigService = tlsCfg
+	}
+
+	if verboseCfg, ok := cfgSvc.(verboseConfigService); ok {
+		optCfg.verboseConfigService = verboseCfg
+	}
+
+	if metricsCfg, ok := cfgSvc.(metricsConfigService); ok {
+		optCfg.metricsConfigService = metricsCfg
+	}
+
+	if transitionsLogCfg, ok := cfgSvc.(transitionsLogConfigService); ok {
+		optCfg.transitionsLogConfigService = transitionsLogCfg
+	}
+
+	if eventsStreamCfg, ok := cfgSvc.(eventsStreamConfigService); ok {
+		optCfg.eventsStreamConfigService = eventsStreamCfg
+	}
+
+	if statusPageCfg, ok := cfgSvc.(statusPageConfigService); ok {
+		optCfg.statusPageConfigService = statusPageCfg
+	}
+
+	if historyCfg, ok := cfgSvc.(historyConfigService); ok {
+		optCfg.historyConfigService = historyCfg
+	}
+
+	if flappingCfg, ok := cfgSvc.(flappingConfigService); ok {
+		optCfg.flappingConfigService = flappingCfg
+	}
+
+	return optCfg
+}
+
 type unitConfig struct {
 	HTTPPath         string
 	ProbeName        string
+	TLSCertPath      string
+	TLSKeyPath       string
+	TLSClientCAPath  string
 	HTTPListenPort   uint
+	HistorySize      uint
 	HTTPReadTimeout  time.Duration
 	HTTPWriteTimeout time.Duration
+	TLSEnabled       bool
+
+	FlappingHoldStatus    CheckStatus
+	FlappingWindowSize    uint
+	FlappingHighThreshold float64
+	FlappingLowThreshold  float64
+	FlappingEnabled       bool
 }
 
 func (p *unitConfig) GetListenAddress() string {
@@ -205,3 +658,35 @@ func (p *unitConfig) GetRequestURL() string {
 func (p *unitConfig) GetProbeName() string {
 	return p.ProbeName
 }
+
+func (p *unitConfig) GetHistorySize() uint {
+	return p.HistorySize
+}
+
+func (p *unitConfig) GetCheckUnitParams() *checkUnitParams {
+	return &checkUnitParams{
+		HistorySize: p.HistorySize,
+
+		FlappingEnabled:       p.FlappingEnabled,
+		FlappingWindowSize:    p.FlappingWindowSize,
+		FlappingHighThreshold: p.FlappingHighThreshold,
+		FlappingLowThreshold:  p.FlappingLowThreshold,
+		FlappingHoldStatus:    p.FlappingHoldStatus,
+	}
+}
+
+func (p *unitConfig) IsTLSEnabled() bool {
+	return p.TLSEnabled
+}
+
+func (p *unitConfig) GetTLSCertPath() string {
+	return p.TLSCertPath
+}
+
+func (p *unitConfig) GetTLSKeyPath() string {
+	return p.TLSKeyPath
+}
+
+func (p *unitConfig) GetTLSClientCAPath() string {
+	return p.TLSClientCAPath
+}
@@ -0,0 +1,187 @@
+/*
+ *
+ *
+ * MIT NON-AI License
+ *
+ * Copyright (c) 2022-2024 Aleksei Kotelnikov(gudron2s@gmail.com)
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy of the software and associated documentation files (the "Software"),
+ * to deal in the Software without restriction, including without limitation the rights to use, copy, modify, merge, publish, distribute, sublicense,
+ * and/or sell copies of the Software, and to permit persons to whom the Software is furnished to do so, subject to the following conditions.
+ *
+ * The above copyright notice and this permission notice shall be included in all copies or substantial portions of the Software.
+ *
+ * In addition, the following restrictions apply:
+ *
+ * 1. The Software and any modifications made to it may not be used for the purpose of training or improving machine learning algorithms,
+ * including but not limited to artificial intelligence, natural language processing, or data mining. This condition applies to any derivatives,
+ * modifications, or updates based on the Software code. Any usage of the Software in an AI-training dataset is considered a breach of this License.
+ *
+ * 2. The Software may not be included in any dataset used for training or improving machine learning algorithms,
+ * including but not limited to artificial intelligence, natural language processing, or data mining.
+ *
+ * 3. Any person or organization found to be in violation of these restrictions will be subject to legal action and may be held liable
+ * for any damages resulting from such use.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM,
+ * DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE
+ * OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+ *
+ */
+
+package healthcheck
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	eventsStreamSnapshotEvent   = "snapshot"
+	eventsStreamTransitionEvent = "transition"
+
+	defaultEventsStreamHeartbeatInterval = time.Second * 15
+)
+
+// httpEventsStreamHandler - Server-Sent Events stream of health state. Sends snapshot of all probes on connect,
+// after that sends every check unit state transition. Stream closed on health checker stop
+type httpEventsStreamHandler struct {
+	l *slog.Logger
+
+	events       *eventsHub
+	snapshotFunc func() []*ProbeReport
+
+	heartbeatInterval time.Duration
+	maxConnections    int64
+
+	connections atomic.Int64
+}
+
+func (h *httpEventsStreamHandler) ServeHTTP(respWriter http.ResponseWriter, httpReq *http.Request) {
+	connections := h.connections.Add(1)
+	defer h.connections.Add(-1)
+
+	// zero max connections means unlimited count of connections
+	if h.maxConnections > 0 && connections > h.maxConnections {
+		respWriter.Header().Add("Content-Type", "text/plain")
+		respWriter.WriteHeader(http.StatusServiceUnavailable)
+		_, _ = respWriter.Write([]byte("too many events stream connections"))
+
+		return
+	}
+
+	respController := http.NewResponseController(respWriter)
+
+	// probe http-server write timeout must not interrupt long-lived stream
+	err := respController.SetWriteDeadline(time.Time{})
+	if err != nil {
+		h.l.Warn("unable to reset write deadline of events stream", slog.Any(ErrorTag, err))
+	}
+
+	// subscription must be created before snapshot, so no one transition will be lost
+	subscription := h.events.Subscribe(DefaultEventsBufferSize)
+	defer subscription.Unsubscribe()
+
+	respWriter.Header().Set("Content-Type", "text/event-stream")
+	respWriter.Header().Set("Cache-Control", "no-cache")
+	respWriter.Header().Set("Connection", "keep-alive")
+	respWriter.Header().Set("X-Accel-Buffering", "no")
+	respWriter.WriteHeader(http.StatusOK)
+
+	err = h.writeEvent(respWriter, respController, eventsStreamSnapshotEvent, h.snapshotFunc())
+	if err != nil {
+		return
+	}
+
+	heartbeatTicker := time.NewTicker(h.heartbeatInterval)
+	defer heartbeatTicker.Stop()
+
+	for {
+		select {
+		case <-httpReq.Context().Done():
+			return
+
+		case event, isOpen := <-subscription.Events():
+			if !isOpen {
+				return
+			}
+
+			err = h.writeEvent(respWriter, respController, eventsStreamTransitionEvent, event)
+
+		case <-heartbeatTicker.C:
+			err = h.writeRaw(respWriter, respController, []byte(": heartbeat\n\n"))
+		}
+
+		if err != nil {
+			return
+		}
+	}
+}
+
+func (h *httpEventsStreamHandler) writeEvent(respWriter http.ResponseWriter,
+	respController *http.ResponseController,
+	eventName string,
+	data any,
+) error {
+	rawData, err := json.Marshal(data)
+	if err != nil {
+		h.l.Error("unable to marshal events stream data", slog.Any(ErrorTag, err))
+
+		return err
+	}
+
+	message := make([]byte, 0, len(rawData)+len(eventName)+16)
+	message = append(message, "event: "+eventName+"\ndata: "...)
+	message = append(message, rawData...)
+	message = append(message, "\n\n"...)
+
+	return h.writeRaw(respWriter, respController, message)
+}
+
+func (h *httpEventsStreamHandler) writeRaw(respWriter http.ResponseWriter,
+	respController *http.ResponseController,
+	message []byte,
+) error {
+	_, err := respWriter.Write(message)
+	if err != nil {
+		h.l.Debug("unable to write events stream message", slog.Any(ErrorTag, err))
+
+		return err
+	}
+
+	err = respController.Flush()
+	if err != nil {
+		h.l.Debug("unable to flush events stream message", slog.Any(ErrorTag, err))
+
+		return err
+	}
+
+	return nil
+}
+
+func newHTTPEventsStreamHandler(logger *slog.Logger,
+	events *eventsHub,
+	snapshotFunc func() []*ProbeReport,
+	heartbeatInterval time.Duration,
+	maxConnections uint,
+) *httpEventsStreamHandler {
+	if heartbeatInterval <= 0 {
+		heartbeatInterval = defaultEventsStreamHeartbeatInterval
+	}
+
+	return &httpEventsStreamHandler{
+		l: logger,
+
+		events:       events,
+		snapshotFunc: snapshotFunc,
+
+		heartbeatInterval: heartbeatInterval,
+		maxConnections:    int64(maxConnections),
+
+		connections: atomic.Int64{},
+	}
+}
@@ -0,0 +1,200 @@
+/*
+ *
+ *
+ * MIT NON-AI License
+ *
+ * Copyright (c) 2022-2024 Aleksei Kotelnikov(gudron2s@gmail.com)
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy of the software and associated documentation files (the "Software"),
+ * to deal in the Software without restriction, including without limitation the rights to use, copy, modify, merge, publish, distribute, sublicense,
+ * and/or sell copies of the Software, and to permit persons to whom the Software is furnished to do so, subject to the following conditions.
+ *
+ * The above copyright notice and this permission notice shall be included in all copies or substantial portions of the Software.
+ *
+ * In addition, the following restrictions apply:
+ *
+ * 1. The Software and any modifications made to it may not be used for the purpose of training or improving machine learning algorithms,
+ * including but not limited to artificial intelligence, natural language processing, or data mining. This condition applies to any derivatives,
+ * modifications, or updates based on the Software code. Any usage of the Software in an AI-training dataset is considered a breach of this License.
+ *
+ * 2. The Software may not be included in any dataset used for training or improving machine learning algorithms,
+ * including but not limited to artificial intelligence, natural language processing, or data mining.
+ *
+ * 3. Any person or organization found to be in violation of these restrictions will be subject to legal action and may be held liable
+ * for any damages resulting from such use.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM,
+ * DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE
+ * OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+ *
+ */
+package healthcheck
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+type sseMessage struct {
+	event string
+	data  string
+	// comment - text of comment line, e.g. heartbeat
+	comment string
+}
+
+// readSSEMessage - reads one message of Server-Sent Events stream, till empty line
+func readSSEMessage(t *testing.T, reader *bufio.Reader) sseMessage {
+	t.Helper()
+
+	message := sseMessage{event: "", data: "", comment: ""}
+
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			t.Fatalf("unable to read events stream: %s", err)
+		}
+
+		line = strings.TrimSuffix(line, "\n")
+
+		switch {
+		case line == "":
+			return message
+		case strings.HasPrefix(line, ":"):
+			message.comment = strings.TrimSpace(strings.TrimPrefix(line, ":"))
+		case strings.HasPrefix(line, "event: "):
+			message.event = strings.TrimPrefix(line, "event: ")
+		case strings.HasPrefix(line, "data: "):
+			message.data = strings.TrimPrefix(line, "data: ")
+		}
+	}
+}
+
+func connectEventsStream(t *testing.T, ctx context.Context, url string) *http.Response {
+	t.Helper()
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		t.Fatalf("unable to create request: %s", err)
+	}
+
+	resp, err := http.DefaultClient.Do(httpReq)
+	if err != nil {
+		t.Fatalf("unable to connect to events stream: %s", err)
+	}
+
+	t.Cleanup(func() {
+		_ = resp.Body.Close()
+	})
+
+	return resp
+}
+
+func TestHTTPEventsStreamHandler(t *testing.T) {
+	hub := newEventsHub()
+
+	snapshot := []*ProbeReport{{
+		Probe:     ProbeNameLiveness,
+		Status:    CheckStatusPass,
+		Timestamp: time.Time{},
+		Duration:  0,
+		Checks:    []*CheckResult{NewCheckResult("database")},
+	}}
+
+	handler := newHTTPEventsStreamHandler(newDiscardLogger(), hub, func() []*ProbeReport {
+		return snapshot
+	}, 50*time.Millisecond, 1)
+
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	resp := connectEventsStream(t, ctx, server.URL)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected status code %d, got %d", http.StatusOK, resp.StatusCode)
+	}
+
+	if resp.Header.Get("Content-Type") != "text/event-stream" {
+		t.Fatalf("unexpected content type: %s", resp.Header.Get("Content-Type"))
+	}
+
+	reader := bufio.NewReader(resp.Body)
+
+	t.Run("snapshot on connect", func(t *testing.T) {
+		message := readSSEMessage(t, reader)
+		if message.event != eventsStreamSnapshotEvent {
+			t.Fatalf("expected %s event, got %+v", eventsStreamSnapshotEvent, message)
+		}
+
+		reports := make([]*ProbeReport, 0)
+		if err := json.Unmarshal([]byte(message.data), &reports); err != nil {
+			t.Fatalf("unable to unmarshal snapshot: %s", err)
+		}
+
+		if len(reports) != 1 || reports[0].Probe != ProbeNameLiveness || len(reports[0].Checks) != 1 {
+			t.Fatalf("unexpected snapshot: %s", message.data)
+		}
+	})
+
+	t.Run("max connections limit", func(t *testing.T) {
+		rejectedResp := connectEventsStream(t, ctx, server.URL)
+		if rejectedResp.StatusCode != http.StatusServiceUnavailable {
+			t.Fatalf("expected status code %d, got %d", http.StatusServiceUnavailable, rejectedResp.StatusCode)
+		}
+	})
+
+	t.Run("transition event", func(t *testing.T) {
+		hub.Publish(StateEvent{
+			Probe:      ProbeNameLiveness,
+			CheckName:  "database",
+			PrevStatus: CheckStatusPass,
+			Status:     CheckStatusFail,
+			Error:      "connection refused",
+			Timestamp:  time.Time{},
+		})
+
+		// heartbeat comments may be sent before transition event
+		message := readSSEMessage(t, reader)
+		for message.comment != "" {
+			message = readSSEMessage(t, reader)
+		}
+
+		if message.event != eventsStreamTransitionEvent {
+			t.Fatalf("expected %s event, got %+v", eventsStreamTransitionEvent, message)
+		}
+
+		event := StateEvent{} //nolint:exhaustruct // filled by unmarshal
+		if err := json.Unmarshal([]byte(message.data), &event); err != nil {
+			t.Fatalf("unable to unmarshal event: %s", err)
+		}
+
+		if event.CheckName != "database" || event.Status != CheckStatusFail {
+			t.Fatalf("unexpected event: %+v", event)
+		}
+	})
+
+	t.Run("heartbeat", func(t *testing.T) {
+		message := readSSEMessage(t, reader)
+		if message.comment != "heartbeat" {
+			t.Fatalf("expected heartbeat comment, got %+v", message)
+		}
+	})
+
+	t.Run("stream closed on hub close", func(t *testing.T) {
+		hub.Close()
+
+		_, err := io.ReadAll(reader)
+		if err != nil {
+			t.Fatalf("expected end of stream, got %s", err)
+		}
+	})
+}
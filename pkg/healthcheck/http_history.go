@@ -0,0 +1,115 @@
+/*
+ *
+ *
+ * MIT NON-AI License
+ *
+ * Copyright (c) 2022-2024 Aleksei Kotelnikov(gudron2s@gmail.com)
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy of the software and associated documentation files (the "Software"),
+ * to deal in the Software without restriction, including without limitation the rights to use, copy, modify, merge, publish, distribute, sublicense,
+ * and/or sell copies of the Software, and to permit persons to whom the Software is furnished to do so, subject to the following conditions.
+ *
+ * The above copyright notice and this permission notice shall be included in all copies or substantial portions of the Software.
+ *
+ * In addition, the following restrictions apply:
+ *
+ * 1. The Software and any modifications made to it may not be used for the purpose of training or improving machine learning algorithms,
+ * including but not limited to artificial intelligence, natural language processing, or data mining. This condition applies to any derivatives,
+ * modifications, or updates based on the Software code. Any usage of the Software in an AI-training dataset is considered a breach of this License.
+ *
+ * 2. The Software may not be included in any dataset used for training or improving machine learning algorithms,
+ * including but not limited to artificial intelligence, natural language processing, or data mining.
+ *
+ * 3. Any person or organization found to be in violation of these restrictions will be subject to legal action and may be held liable
+ * for any damages resulting from such use.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM,
+ * DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE
+ * OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+ *
+ */
+
+package healthcheck
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"strconv"
+)
+
+const (
+	historyProbeQueryParam = "probe"
+	historyCheckQueryParam = "check"
+	historyLimitQueryParam = "limit"
+)
+
+// httpHistoryHandler - JSON history of check units. Supports filter by probe and check name and limit of results,
+// e.g. /history?probe=liveness_checker_unit&check=postgres&limit=10
+type httpHistoryHandler struct {
+	l *slog.Logger
+
+	historyFunc func(checkName string, limit int) []*ProbeHistory
+}
+
+func (h *httpHistoryHandler) ServeHTTP(respWriter http.ResponseWriter, httpReq *http.Request) {
+	query := httpReq.URL.Query()
+
+	limit := 0
+	if query.Has(historyLimitQueryParam) {
+		var err error
+
+		limit, err = strconv.Atoi(query.Get(historyLimitQueryParam))
+		if err != nil || limit < 0 {
+			respWriter.Header().Add("Content-Type", "text/plain")
+			respWriter.WriteHeader(http.StatusBadRequest)
+			_, _ = respWriter.Write([]byte("invalid limit query param"))
+
+			return
+		}
+	}
+
+	probesHistory := h.historyFunc(query.Get(historyCheckQueryParam), limit)
+
+	probeName := query.Get(historyProbeQueryParam)
+	if probeName != "" {
+		filtered := make([]*ProbeHistory, 0, 1)
+
+		for _, probeHistory := range probesHistory {
+			if probeHistory.Probe == probeName {
+				filtered = append(filtered, probeHistory)
+			}
+		}
+
+		probesHistory = filtered
+	}
+
+	respBody, err := json.Marshal(probesHistory)
+	if err != nil {
+		h.l.Error("unable to marshal history response", slog.Any(ErrorTag, err))
+
+		respWriter.WriteHeader(http.StatusInternalServerError)
+
+		return
+	}
+
+	respWriter.Header().Add("Content-Type", "application/json")
+	respWriter.Header().Add("Cache-Control", "no-cache")
+	respWriter.WriteHeader(http.StatusOK)
+
+	_, err = respWriter.Write(respBody)
+	if err != nil {
+		h.l.Error("unable to write history response", slog.Any(ErrorTag, err))
+	}
+}
+
+func newHTTPHistoryHandler(logger *slog.Logger,
+	historyFunc func(checkName string, limit int) []*ProbeHistory,
+) *httpHistoryHandler {
+	return &httpHistoryHandler{
+		l: logger,
+
+		historyFunc: historyFunc,
+	}
+}
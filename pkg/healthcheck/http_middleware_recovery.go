@@ -51,36 +51,42 @@ type middleware struct {
 
 type middlewareRecovery struct {
 	l *slog.Logger
+
+	next http.Handler
 }
 
-func newRecoveryMiddleware(l *slog.Logger) *middlewareRecovery {
+func newRecoveryMiddleware(l *slog.Logger, next http.Handler) *middlewareRecovery {
 	return &middlewareRecovery{
 		l: l,
+
+		next: next,
 	}
 }
 
-func (m *middlewareRecovery) ServeHTTP(respWriter http.ResponseWriter, _ *http.Request) {
+func (m *middlewareRecovery) ServeHTTP(respWriter http.ResponseWriter, httpReq *http.Request) {
 	defer func() {
 		recoverErr := recover()
 		if recoverErr != nil {
-			respWriter.WriteHeader(http.StatusInternalServerError)
 			respWriter.Header().Add("Content-Type", "text/plain")
+			respWriter.WriteHeader(http.StatusInternalServerError)
 
 			respText := fmt.Sprintf("%e\n%+v\n", ErrHealthCheckRecovery, recoverErr)
 
 			_, writeErr := respWriter.Write([]byte(respText))
 			if writeErr != nil {
-				m.l.Error("unable to write response", writeErr,
+				m.l.Error("unable to write response", slog.Any(ErrorTag, writeErr),
 					slog.Time(RecoveryTimeTag, time.Now()),
 				)
 			}
 
-			m.l.Error("called recovery flow", ErrHealthCheckRecovery,
+			m.l.Error("called recovery flow", slog.Any(ErrorTag, ErrHealthCheckRecovery),
 				slog.Any(RecoveryErrTag, recoverErr),
 				slog.Time(RecoveryTimeTag, time.Now()),
 			)
 		}
 	}()
+
+	m.next.ServeHTTP(respWriter, httpReq)
 }
 
 func (m *middleware) With(next http.Handler) *middleware {
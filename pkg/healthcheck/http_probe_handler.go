@@ -33,65 +33,126 @@
 package healthcheck
 
 import (
+	"encoding/json"
 	"log/slog"
 	"net/http"
-	"sync"
+	"strconv"
+)
+
+const (
+	verboseQueryParam = "verbose"
 )
 
 type httpHandler struct {
 	l *slog.Logger
 
-	probes []probeService
-
-	mu sync.RWMutex
+	checker *probeChecker
+	access  *accessPolicy
 }
 
-func (h *httpHandler) AddProbe(svc probeService) {
-	h.mu.Lock()
-	defer h.mu.Unlock()
-
-	h.probes = append(h.probes, svc)
+func (h *httpHandler) AddProbe(svc probeService) error {
+	return h.checker.AddUnit(svc)
 }
 
 func (h *httpHandler) ServeHTTP(respWriter http.ResponseWriter, httpReq *http.Request) {
-	h.mu.RLock()
-	defer h.mu.RUnlock()
+	isVerbose := h.isVerboseRequest(httpReq)
+	if isVerbose {
+		accessStatusCode := h.access.Authorize(httpReq)
+		if accessStatusCode != http.StatusOK {
+			h.l.Debug("verbose healthcheck report request rejected",
+				slog.String(RemoteAddrTag, httpReq.RemoteAddr),
+				slog.Int(StatusCodeTag, accessStatusCode))
+
+			writeAccessDeniedResponse(respWriter, accessStatusCode)
+
+			return
+		}
+	}
+
+	report := h.checker.Run(httpReq.Context())
 
-	var isHealed = true
+	statusCode := http.StatusOK
+	message := AppHealthyMessage
 
-	for i := 0; i != len(h.probes); i++ {
-		isHealed = isHealed && h.probes[i].IsHealed(httpReq.Context())
+	if !report.IsHealthy() {
+		statusCode = http.StatusTeapot
+		message = AppUnHealthyMessage
 	}
 
-	if isHealed {
-		h.writeResponse(respWriter, http.StatusOK, AppHealthyMessage)
+	if isVerbose {
+		h.writeJSONResponse(respWriter, statusCode, report)
 
 		return
 	}
 
-	h.writeResponse(respWriter, http.StatusTeapot, AppUnHealthyMessage)
+	h.writeResponse(respWriter, statusCode, message)
+}
+
+func (h *httpHandler) isVerboseRequest(httpReq *http.Request) bool {
+	values, isExists := httpReq.URL.Query()[verboseQueryParam]
+	if !isExists {
+		return false
+	}
+
+	if len(values) == 0 || values[0] == "" {
+		return true
+	}
+
+	isVerbose, err := strconv.ParseBool(values[0])
+	if err != nil {
+		return false
+	}
+
+	return isVerbose
 }
 
 func (h *httpHandler) writeResponse(respWriter http.ResponseWriter,
 	statusCode int,
 	message string,
 ) {
-	respWriter.WriteHeader(statusCode)
 	respWriter.Header().Add("Content-Type", "text/plain")
+	respWriter.WriteHeader(statusCode)
 
 	_, writeErr := respWriter.Write([]byte(message))
 	if writeErr != nil {
-		h.l.Error("unable to write http probe response", writeErr)
+		h.l.Error("unable to write http probe response", slog.Any(ErrorTag, writeErr))
+
+		return
+	}
+}
+
+func (h *httpHandler) writeJSONResponse(respWriter http.ResponseWriter,
+	statusCode int,
+	body any,
+) {
+	respBody, err := json.Marshal(body)
+	if err != nil {
+		h.l.Error("unable to marshal http probe response", slog.Any(ErrorTag, err))
+
+		h.writeResponse(respWriter, http.StatusInternalServerError, AppUnHealthyMessage)
+
+		return
+	}
+
+	respWriter.Header().Add("Content-Type", "application/json")
+	respWriter.WriteHeader(statusCode)
+
+	_, writeErr := respWriter.Write(respBody)
+	if writeErr != nil {
+		h.l.Error("unable to write http probe response", slog.Any(ErrorTag, writeErr))
 
 		return
 	}
 }
 
-func newHTTPHandler(logger *slog.Logger) *httpHandler {
+func newHTTPHandler(logger *slog.Logger,
+	checker *probeChecker,
+	access *accessPolicy,
+) *httpHandler {
 	return &httpHandler{
-		mu: sync.RWMutex{},
+		l: logger,
 
-		l:      logger,
-		probes: nil,
+		checker: checker,
+		access:  access,
 	}
 }
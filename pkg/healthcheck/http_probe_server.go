@@ -34,8 +34,14 @@ package healthcheck
 
 import (
 	"context"
+	"errors"
 	"log/slog"
 	"net/http"
+	"time"
+)
+
+const (
+	probeShutdownTimeout = time.Second * 5
 )
 
 type probeUnit struct {
@@ -45,47 +51,118 @@ type probeUnit struct {
 	cfg *unitConfig
 
 	httpSrv      *http.Server
+	httpMux      *http.ServeMux
 	probeHandler *httpHandler
+	tlsReloader  *tlsConfigReloader
 
 	applicationPID int
 }
 
 func (s *probeUnit) ListenAndServe(ctx context.Context) error {
-	err := s.httpSrv.ListenAndServe()
-	if err != nil {
-		s.l.Error("unable to listen and serve http server", err)
+	serveErrCh := make(chan error, 1)
 
-		return s.e.ErrorOnly(err)
-	}
+	go func() {
+		serveErrCh <- s.listenAndServe()
+	}()
 
 	s.l.Info("healthcheck probe server run successfully")
 
-	<-ctx.Done()
+	select {
+	case err := <-serveErrCh:
+		if err != nil && !errors.Is(err, http.ErrServerClosed) {
+			s.l.Error("unable to listen and serve http server", slog.Any(ErrorTag, err))
+
+			return s.e.ErrorOnly(err)
+		}
+
+		return nil
+
+	case <-ctx.Done():
+	}
+
+	// parent context already canceled, so shutdown uses own context with timeout
+	shutdownCtx, cancelFunc := context.WithTimeout(context.WithoutCancel(ctx), probeShutdownTimeout)
+	defer cancelFunc()
 
-	err = s.httpSrv.Shutdown(ctx)
+	err := s.httpSrv.Shutdown(shutdownCtx)
+	if err == nil {
+		return nil
+	}
+
+	s.l.Error("unable to shutdown http server", slog.Any(ErrorTag, err))
+
+	err = s.httpSrv.Close()
 	if err != nil {
-		s.l.Error("unable to shutdown http server", err)
+		s.l.Error("unable to close http server", slog.Any(ErrorTag, err))
 
 		return s.e.ErrorOnly(err)
 	}
 
-	err = s.httpSrv.Close()
+	return nil
+}
+
+func (s *probeUnit) listenAndServe() error {
+	if s.tlsReloader == nil {
+		return s.httpSrv.ListenAndServe()
+	}
+
+	err := s.tlsReloader.Load()
 	if err != nil {
-		s.l.Error("unable to close http server", err)
+		return s.e.ErrorOnly(err)
+	}
+
+	s.httpSrv.TLSConfig = s.tlsReloader.GetTLSConfig()
+
+	// certificate and key already loaded by tlsReloader, so cert and key file params must be empty
+	return s.httpSrv.ListenAndServeTLS("", "")
+}
 
+func (s *probeUnit) AddProbeUnit(unit probeService) error {
+	err := s.probeHandler.AddProbe(unit)
+	if err != nil {
 		return s.e.ErrorOnly(err)
 	}
 
 	return nil
 }
 
-func (s *probeUnit) AddProbeUnit(unit probeService) {
-	s.probeHandler.AddProbe(unit)
+// GetDependencyGraph - returns check units dependencies graph of probe
+func (s *probeUnit) GetDependencyGraph() *DependencyGraph {
+	return s.probeHandler.checker.Graph()
+}
+
+// GetSnapshot - returns probe report by last results of check units, without units execution
+func (s *probeUnit) GetSnapshot() *ProbeReport {
+	return s.probeHandler.checker.Snapshot()
+}
+
+// GetHistory - returns up to limit last results of check units. Results of all units returned if checkName is empty
+func (s *probeUnit) GetHistory(checkName string, limit int) *ProbeHistory {
+	return s.probeHandler.checker.History(checkName, limit)
+}
+
+// GetHTTPHandler - returns handler of all probe http-server routes, with probe middlewares
+func (s *probeUnit) GetHTTPHandler() http.Handler {
+	return s.httpMux
+}
+
+// AddCheckResultObserver - add observer of probe check units results. Must be called before ListenAndServe
+func (s *probeUnit) AddCheckResultObserver(observer checkResultObserver) {
+	s.probeHandler.checker.AddObserver(observer)
+}
+
+// AddHTTPHandler - add additional handler to probe http-server, e.g. metrics handler.
+// Must be called before ListenAndServe
+func (s *probeUnit) AddHTTPHandler(path string, handler http.Handler) {
+	s.httpMux.Handle(path, newRecoveryMiddleware(s.l, handler))
 }
 
 func newHTPPHealthCheckerServer(logFactorySvc loggerService,
 	errFmtSvc errorFormatterService,
 	configSvc *unitConfig,
+	access *accessPolicy,
+	metrics *healthMetrics,
+	tracer *healthTracer,
 ) *probeUnit {
 	logger := logFactorySvc.NewSlogNamedLoggerEntry("healthcheck_unit",
 		slog.String(ListenAddressTag, configSvc.GetListenAddress()),
@@ -94,9 +171,13 @@ func newHTPPHealthCheckerServer(logFactorySvc loggerService,
 	mux := http.NewServeMux()
 
 	httpMiddleware := newMiddleware(logger)
-	handler := newHTTPHandler(logger)
-	handlerWithMiddleware := httpMiddleware.With(handler).
-		With(newRecoveryMiddleware(logger))
+	checker := newProbeChecker(configSvc.GetProbeName(), tracer, configSvc.GetCheckUnitParams())
+	checker.AddObserver(metrics)
+
+	handler := newHTTPHandler(logger, checker, access)
+	handlerWithMiddleware := httpMiddleware.With(newMetricsMiddleware(configSvc.GetProbeName(), metrics,
+		newTracingMiddleware(configSvc.GetProbeName(), tracer,
+			newRecoveryMiddleware(logger, handler))))
 
 	mux.Handle(configSvc.GetRequestURL(), handlerWithMiddleware.GetHTTPHandler())
 
@@ -109,6 +190,11 @@ func newHTPPHealthCheckerServer(logFactorySvc loggerService,
 		ErrorLog:     logFactorySvc.NewStdLoggerEntry(),
 	}
 
+	var tlsReloader *tlsConfigReloader
+	if configSvc.IsTLSEnabled() {
+		tlsReloader = newTLSConfigReloader(logger, errFmtSvc, configSvc)
+	}
+
 	return &probeUnit{
 		l: logger,
 		e: errFmtSvc,
@@ -118,6 +204,8 @@ func newHTPPHealthCheckerServer(logFactorySvc loggerService,
 		applicationPID: -1,
 
 		httpSrv:      server,
+		httpMux:      mux,
 		probeHandler: handler,
+		tlsReloader:  tlsReloader,
 	}
 }
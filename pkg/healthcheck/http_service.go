@@ -35,7 +35,10 @@ package healthcheck
 import (
 	"context"
 	"errors"
+	"io"
 	"log/slog"
+	"net/http"
+	"strings"
 )
 
 var (
@@ -46,7 +49,10 @@ type httpHealthChecker struct {
 	l *slog.Logger
 	e errorFormatterService
 
-	probes [3]probeHTTPServer // liveness, rediness, startup
+	probes  [3]probeHTTPServer // liveness, rediness, startup
+	metrics *healthMetrics
+	tracer  *healthTracer
+	events  *eventsHub
 }
 
 func (s *httpHealthChecker) ListenAndServe(ctx context.Context) error {
@@ -58,24 +64,113 @@ func (s *httpHealthChecker) ListenAndServe(ctx context.Context) error {
 		go func(probeSrv probeHTTPServer) {
 			err := probeSrv.ListenAndServe(ctx)
 			if err != nil {
-				s.l.Error("unable to start listen and server process for probe", err)
+				s.l.Error("unable to start listen and server process for probe", slog.Any(ErrorTag, err))
 			}
 		}(probe)
 	}
 
+	go func() {
+		<-ctx.Done()
+
+		s.events.Close()
+	}()
+
 	s.l.Info("all probes successfully listen up")
 
 	return nil
 }
 
+// WriteMetrics - write probes and check units metrics in prometheus text exposition format,
+// e.g. for serve metrics by application http-server instead of probe http-server
+func (s *httpHealthChecker) WriteMetrics(writer io.Writer) error {
+	return s.metrics.Encode(writer)
+}
+
+// GetSnapshot - returns reports of all enabled probes by last results of check units, without units execution
+func (s *httpHealthChecker) GetSnapshot() []*ProbeReport {
+	reports := make([]*ProbeReport, 0, len(s.probes))
+
+	for _, probe := range s.probes {
+		if probe == nil {
+			continue
+		}
+
+		reports = append(reports, probe.GetSnapshot())
+	}
+
+	return reports
+}
+
+// GetHistory - returns up to limit last results of check units of all enabled probes.
+// Results of all units returned if checkName is empty, all stored results returned if limit is not positive
+func (s *httpHealthChecker) GetHistory(checkName string, limit int) []*ProbeHistory {
+	probesHistory := make([]*ProbeHistory, 0, len(s.probes))
+
+	for _, probe := range s.probes {
+		if probe == nil {
+			continue
+		}
+
+		probesHistory = append(probesHistory, probe.GetHistory(checkName, limit))
+	}
+
+	return probesHistory
+}
+
+// Subscribe - subscribe to check units state transition events. Events delivered over returned subscription channel.
+// Fan-out is non-blocking - if subscriber buffer is full, event is dropped for this subscriber.
+// Subscription channel closed on Unsubscribe call or on health checker stop
+func (s *httpHealthChecker) Subscribe(bufferSize int) *eventSubscription {
+	return s.events.Subscribe(bufferSize)
+}
+
+// SubscribeFunc - same with Subscribe, but events delivered to callback function called in separate goroutine
+func (s *httpHealthChecker) SubscribeFunc(bufferSize int, callback func(event StateEvent)) *eventSubscription {
+	return s.events.SubscribeFunc(bufferSize, callback)
+}
+
+// WriteDependencyGraph - write check units dependencies graph of all enabled probes in DOT or Mermaid format
+func (s *httpHealthChecker) WriteDependencyGraph(writer io.Writer, format DependencyGraphFormat) error {
+	graphs := make([]*DependencyGraph, 0, len(s.probes))
+
+	for _, probe := range s.probes {
+		if probe == nil {
+			continue
+		}
+
+		graphs = append(graphs, probe.GetDependencyGraph())
+	}
+
+	err := writeDependencyGraph(writer, format, graphs)
+	if err != nil {
+		return s.e.ErrorOnly(err)
+	}
+
+	return nil
+}
+
+// SetTracer - enable instrumentation of probe requests and check units execution, e.g. by OpenTelemetry tracer
+// from otelhealth package. Span context of probe request passed to IsHealed function of probe unit
+func (s *httpHealthChecker) SetTracer(tracer Tracer) {
+	s.tracer.SetTracer(tracer)
+}
+
+// GetProbeHTTPHandler - returns handler of probe http-server routes, e.g. for serve probe by application http-server
+// or by httptest server. Error returned if probe not enabled
+func (s *httpHealthChecker) GetProbeHTTPHandler(probeIndex ProbeIndex) (http.Handler, error) {
+	if int(probeIndex) >= len(s.probes) || s.probes[probeIndex] == nil {
+		return nil, s.e.ErrorOnly(ErrProbeTypeNotEnabled)
+	}
+
+	return s.probes[probeIndex].GetHTTPHandler(), nil
+}
+
 func (s *httpHealthChecker) AddLivenessProbeUnit(probe probeService) error {
 	if s.probes[LivenessProbeIndex] == nil {
 		return s.e.ErrorOnly(ErrProbeTypeNotEnabled)
 	}
 
-	s.probes[LivenessProbeIndex].AddProbeUnit(probe)
-
-	return nil
+	return s.probes[LivenessProbeIndex].AddProbeUnit(probe)
 }
 
 func (s *httpHealthChecker) AddRedinessProbeUnit(probe probeService) error {
@@ -83,9 +178,7 @@ func (s *httpHealthChecker) AddRedinessProbeUnit(probe probeService) error {
 		return s.e.ErrorOnly(ErrProbeTypeNotEnabled)
 	}
 
-	s.probes[RedinessProbeIndex].AddProbeUnit(probe)
-
-	return nil
+	return s.probes[RedinessProbeIndex].AddProbeUnit(probe)
 }
 
 func (s *httpHealthChecker) AddStartupProbeUnit(probe probeService) error {
@@ -93,15 +186,22 @@ func (s *httpHealthChecker) AddStartupProbeUnit(probe probeService) error {
 		return s.e.ErrorOnly(ErrProbeTypeNotEnabled)
 	}
 
-	s.probes[StartupProbeIndex].AddProbeUnit(probe)
-
-	return nil
+	return s.probes[StartupProbeIndex].AddProbeUnit(probe)
 }
 
 func NewHTTPHealthChecker(logFactorySvc loggerService,
 	errFmtSvc errorFormatterService,
 	cfgSvc configService,
 ) *httpHealthChecker {
+	optionalCfg := newOptionalConfig(cfgSvc)
+
+	access := newAccessPolicy(logFactorySvc.NewSlogNamedLoggerEntry("healthcheck_access"), optionalCfg)
+	metrics := newHealthMetrics(logFactorySvc.NewSlogNamedLoggerEntry("healthcheck_metrics"))
+	tracer := newHealthTracer()
+	events := newEventsHub()
+	transitionsLog := newTransitionsLogger(logFactorySvc.NewSlogNamedLoggerEntry("healthcheck_transitions"),
+		optionalCfg.GetTransitionsLogRepeatInterval())
+
 	probes := [3]probeHTTPServer{}
 	if cfgSvc.IsStartupProbeEnable() {
 		probes[StartupProbeIndex] = newHTPPHealthCheckerServer(logFactorySvc,
@@ -110,8 +210,19 @@ func NewHTTPHealthChecker(logFactorySvc loggerService,
 				HTTPReadTimeout:  cfgSvc.GetStartupProbeReadTimeout(),
 				HTTPWriteTimeout: cfgSvc.GetStartupProbeWriteTimeout(),
 				HTTPPath:         cfgSvc.GetStartupProbeRequestPath(),
+				TLSEnabled:       optionalCfg.IsStartupProbeTLSEnable(),
+				TLSCertPath:      optionalCfg.GetStartupProbeTLSCertPath(),
+				TLSKeyPath:       optionalCfg.GetStartupProbeTLSKeyPath(),
+				TLSClientCAPath:  optionalCfg.GetStartupProbeTLSClientCAPath(),
 				ProbeName:        ProbeNameStartup,
-			})
+				HistorySize:      optionalCfg.GetHistorySize(),
+
+				FlappingEnabled:       optionalCfg.IsFlappingDetectionEnable(),
+				FlappingWindowSize:    optionalCfg.GetFlappingWindowSize(),
+				FlappingHighThreshold: optionalCfg.GetFlappingHighThreshold(),
+				FlappingLowThreshold:  optionalCfg.GetFlappingLowThreshold(),
+				FlappingHoldStatus:    optionalCfg.GetFlappingHoldStatus(),
+			}, access, metrics, tracer)
 	}
 
 	if cfgSvc.IsReadinessProbeEnable() {
@@ -121,8 +232,19 @@ func NewHTTPHealthChecker(logFactorySvc loggerService,
 				HTTPReadTimeout:  cfgSvc.GetReadinessProbeReadTimeout(),
 				HTTPWriteTimeout: cfgSvc.GetReadinessProbeWriteTimeout(),
 				HTTPPath:         cfgSvc.GetReadinessProbeRequestPath(),
+				TLSEnabled:       optionalCfg.IsReadinessProbeTLSEnable(),
+				TLSCertPath:      optionalCfg.GetReadinessProbeTLSCertPath(),
+				TLSKeyPath:       optionalCfg.GetReadinessProbeTLSKeyPath(),
+				TLSClientCAPath:  optionalCfg.GetReadinessProbeTLSClientCAPath(),
 				ProbeName:        ProbeNameRediness,
-			})
+				HistorySize:      optionalCfg.GetHistorySize(),
+
+				FlappingEnabled:       optionalCfg.IsFlappingDetectionEnable(),
+				FlappingWindowSize:    optionalCfg.GetFlappingWindowSize(),
+				FlappingHighThreshold: optionalCfg.GetFlappingHighThreshold(),
+				FlappingLowThreshold:  optionalCfg.GetFlappingLowThreshold(),
+				FlappingHoldStatus:    optionalCfg.GetFlappingHoldStatus(),
+			}, access, metrics, tracer)
 	}
 
 	if cfgSvc.IsLivenessProbeEnable() {
@@ -132,15 +254,82 @@ func NewHTTPHealthChecker(logFactorySvc loggerService,
 				HTTPReadTimeout:  cfgSvc.GetLivenessProbeReadTimeout(),
 				HTTPWriteTimeout: cfgSvc.GetLivenessProbeWriteTimeout(),
 				HTTPPath:         cfgSvc.GetLivenessProbeRequestPath(),
+				TLSEnabled:       optionalCfg.IsLivenessProbeTLSEnable(),
+				TLSCertPath:      optionalCfg.GetLivenessProbeTLSCertPath(),
+				TLSKeyPath:       optionalCfg.GetLivenessProbeTLSKeyPath(),
+				TLSClientCAPath:  optionalCfg.GetLivenessProbeTLSClientCAPath(),
 				ProbeName:        ProbeNameLiveness,
-			})
+				HistorySize:      optionalCfg.GetHistorySize(),
+
+				FlappingEnabled:       optionalCfg.IsFlappingDetectionEnable(),
+				FlappingWindowSize:    optionalCfg.GetFlappingWindowSize(),
+				FlappingHighThreshold: optionalCfg.GetFlappingHighThreshold(),
+				FlappingLowThreshold:  optionalCfg.GetFlappingLowThreshold(),
+				FlappingHoldStatus:    optionalCfg.GetFlappingHoldStatus(),
+			}, access, metrics, tracer)
 	}
 
 	healthChecker := &httpHealthChecker{
 		l: logFactorySvc.NewSlogNamedLoggerEntry("healthcheck"),
 		e: errFmtSvc,
 
-		probes: probes,
+		probes:  probes,
+		metrics: metrics,
+		tracer:  tracer,
+		events:  events,
+	}
+
+	var eventsStream *httpEventsStreamHandler
+	if optionalCfg.IsEventsStreamEnable() {
+		eventsStream = newHTTPEventsStreamHandler(logFactorySvc.NewSlogNamedLoggerEntry("healthcheck_events_stream"),
+			events, healthChecker.GetSnapshot,
+			optionalCfg.GetEventsStreamHeartbeatInterval(), optionalCfg.GetEventsStreamMaxConnections())
+	}
+
+	historyLogger := logFactorySvc.NewSlogNamedLoggerEntry("healthcheck_history")
+	history := newAccessMiddleware(access, newHTTPHistoryHandler(historyLogger, healthChecker.GetHistory))
+
+	var statusPage, snapshot http.Handler
+	if optionalCfg.IsStatusPageEnable() {
+		statusPageLogger := logFactorySvc.NewSlogNamedLoggerEntry("healthcheck_status_page")
+
+		statusPage = newAccessMiddleware(access, newHTTPStatusPageHandler(statusPageLogger))
+		snapshot = newAccessMiddleware(access, newHTTPSnapshotHandler(statusPageLogger, healthChecker.GetSnapshot))
+	}
+
+	livenessProbe := probes[LivenessProbeIndex]
+	if livenessProbe != nil && optionalCfg.IsHistoryDumpOnLivenessFailureEnable() {
+		livenessProbe.AddCheckResultObserver(newHistoryDumper(historyLogger,
+			optionalCfg.GetHistoryDumpSize(), livenessProbe.GetHistory))
+	}
+
+	for _, probe := range probes {
+		if probe == nil {
+			continue
+		}
+
+		probe.AddCheckResultObserver(events)
+		probe.AddCheckResultObserver(transitionsLog)
+
+		if optionalCfg.IsMetricsEnable() {
+			probe.AddHTTPHandler(optionalCfg.GetMetricsRequestPath(), metrics)
+		}
+
+		if eventsStream != nil {
+			probe.AddHTTPHandler(optionalCfg.GetEventsStreamRequestPath(), newAccessMiddleware(access, eventsStream))
+		}
+
+		if optionalCfg.IsHistoryEnable() {
+			probe.AddHTTPHandler(optionalCfg.GetHistoryRequestPath(), history)
+		}
+
+		if statusPage != nil {
+			statusPagePath := strings.TrimRight(optionalCfg.GetStatusPageRequestPath(), "/")
+
+			probe.AddHTTPHandler(optionalCfg.GetStatusPageRequestPath(), statusPage)
+			probe.AddHTTPHandler(statusPagePath+statusPageReportPath, snapshot)
+			probe.AddHTTPHandler(statusPagePath+statusPageHistoryPath, history)
+		}
 	}
 
 	return healthChecker
@@ -0,0 +1,123 @@
+/*
+ *
+ *
+ * MIT NON-AI License
+ *
+ * Copyright (c) 2022-2024 Aleksei Kotelnikov(gudron2s@gmail.com)
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy of the software and associated documentation files (the "Software"),
+ * to deal in the Software without restriction, including without limitation the rights to use, copy, modify, merge, publish, distribute, sublicense,
+ * and/or sell copies of the Software, and to permit persons to whom the Software is furnished to do so, subject to the following conditions.
+ *
+ * The above copyright notice and this permission notice shall be included in all copies or substantial portions of the Software.
+ *
+ * In addition, the following restrictions apply:
+ *
+ * 1. The Software and any modifications made to it may not be used for the purpose of training or improving machine learning algorithms,
+ * including but not limited to artificial intelligence, natural language processing, or data mining. This condition applies to any derivatives,
+ * modifications, or updates based on the Software code. Any usage of the Software in an AI-training dataset is considered a breach of this License.
+ *
+ * 2. The Software may not be included in any dataset used for training or improving machine learning algorithms,
+ * including but not limited to artificial intelligence, natural language processing, or data mining.
+ *
+ * 3. Any person or organization found to be in violation of these restrictions will be subject to legal action and may be held liable
+ * for any damages resulting from such use.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM,
+ * DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE
+ * OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+ *
+ */
+
+package healthcheck
+
+import (
+	"embed"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+)
+
+const (
+	statusPageFileName    = "static/status_page.html"
+	statusPageReportPath  = "/report"
+	statusPageHistoryPath = "/history"
+)
+
+//go:embed static/status_page.html
+var statusPageFS embed.FS
+
+// httpStatusPageHandler - human-readable status page of all probes.
+// Page is self-contained and auto-refreshing by JSON report from httpSnapshotHandler
+type httpStatusPageHandler struct {
+	l *slog.Logger
+
+	page []byte
+}
+
+func (h *httpStatusPageHandler) ServeHTTP(respWriter http.ResponseWriter, _ *http.Request) {
+	if h.page == nil {
+		respWriter.Header().Add("Content-Type", "text/plain")
+		respWriter.WriteHeader(http.StatusInternalServerError)
+
+		return
+	}
+
+	respWriter.Header().Add("Content-Type", "text/html; charset=utf-8")
+	respWriter.Header().Add("Cache-Control", "no-cache")
+	respWriter.WriteHeader(http.StatusOK)
+
+	_, err := respWriter.Write(h.page)
+	if err != nil {
+		h.l.Error("unable to write status page response", slog.Any(ErrorTag, err))
+	}
+}
+
+func newHTTPStatusPageHandler(logger *slog.Logger) *httpStatusPageHandler {
+	page, err := statusPageFS.ReadFile(statusPageFileName)
+	if err != nil {
+		logger.Error("unable to read embedded status page", slog.Any(ErrorTag, err))
+	}
+
+	return &httpStatusPageHandler{
+		l: logger,
+
+		page: page,
+	}
+}
+
+// httpSnapshotHandler - JSON list of all probes reports by last results of check units
+type httpSnapshotHandler struct {
+	l *slog.Logger
+
+	snapshotFunc func() []*ProbeReport
+}
+
+func (h *httpSnapshotHandler) ServeHTTP(respWriter http.ResponseWriter, _ *http.Request) {
+	respBody, err := json.Marshal(h.snapshotFunc())
+	if err != nil {
+		h.l.Error("unable to marshal snapshot response", slog.Any(ErrorTag, err))
+
+		respWriter.WriteHeader(http.StatusInternalServerError)
+
+		return
+	}
+
+	respWriter.Header().Add("Content-Type", "application/json")
+	respWriter.Header().Add("Cache-Control", "no-cache")
+	respWriter.WriteHeader(http.StatusOK)
+
+	_, err = respWriter.Write(respBody)
+	if err != nil {
+		h.l.Error("unable to write snapshot response", slog.Any(ErrorTag, err))
+	}
+}
+
+func newHTTPSnapshotHandler(logger *slog.Logger, snapshotFunc func() []*ProbeReport) *httpSnapshotHandler {
+	return &httpSnapshotHandler{
+		l: logger,
+
+		snapshotFunc: snapshotFunc,
+	}
+}
@@ -0,0 +1,133 @@
+/*
+ *
+ *
+ * MIT NON-AI License
+ *
+ * Copyright (c) 2022-2024 Aleksei Kotelnikov(gudron2s@gmail.com)
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy of the software and associated documentation files (the "Software"),
+ * to deal in the Software without restriction, including without limitation the rights to use, copy, modify, merge, publish, distribute, sublicense,
+ * and/or sell copies of the Software, and to permit persons to whom the Software is furnished to do so, subject to the following conditions.
+ *
+ * The above copyright notice and this permission notice shall be included in all copies or substantial portions of the Software.
+ *
+ * In addition, the following restrictions apply:
+ *
+ * 1. The Software and any modifications made to it may not be used for the purpose of training or improving machine learning algorithms,
+ * including but not limited to artificial intelligence, natural language processing, or data mining. This condition applies to any derivatives,
+ * modifications, or updates based on the Software code. Any usage of the Software in an AI-training dataset is considered a breach of this License.
+ *
+ * 2. The Software may not be included in any dataset used for training or improving machine learning algorithms,
+ * including but not limited to artificial intelligence, natural language processing, or data mining.
+ *
+ * 3. Any person or organization found to be in violation of these restrictions will be subject to legal action and may be held liable
+ * for any damages resulting from such use.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM,
+ * DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE
+ * OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+ *
+ */
+package healthcheck
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestHTTPStatusPageHandler(t *testing.T) {
+	policy := newAccessPolicy(newDiscardLogger(), &fakeVerboseConfig{
+		bearerToken:  "s3cr3t-token",
+		allowedCIDRs: nil,
+	})
+
+	handler := newAccessMiddleware(policy, newHTTPStatusPageHandler(newDiscardLogger()))
+
+	t.Run("unauthorized request", func(t *testing.T) {
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/status", nil))
+
+		if recorder.Code != http.StatusUnauthorized {
+			t.Fatalf("expected status code %d, got %d", http.StatusUnauthorized, recorder.Code)
+		}
+
+		if strings.Contains(recorder.Body.String(), "<html") {
+			t.Fatal("status page served to unauthorized request")
+		}
+	})
+
+	t.Run("embedded page", func(t *testing.T) {
+		httpReq := httptest.NewRequest(http.MethodGet, "/status", nil)
+		httpReq.Header.Set("Authorization", "Bearer s3cr3t-token")
+
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, httpReq)
+
+		if recorder.Code != http.StatusOK {
+			t.Fatalf("expected status code %d, got %d", http.StatusOK, recorder.Code)
+		}
+
+		if recorder.Header().Get("Content-Type") != "text/html; charset=utf-8" {
+			t.Fatalf("unexpected content type: %s", recorder.Header().Get("Content-Type"))
+		}
+
+		page := recorder.Body.String()
+		if !strings.Contains(page, "<title>Healthcheck status</title>") {
+			t.Fatal("status page has no title")
+		}
+
+		// page must be self-contained, without external scripts, styles or images
+		externalAssets := regexp.MustCompile(`(?i)(src|href)\s*=\s*["']?(https?:)?//`)
+		if externalAssets.MatchString(page) {
+			t.Fatalf("status page has external asset: %s", externalAssets.FindString(page))
+		}
+	})
+}
+
+func TestHTTPSnapshotHandler(t *testing.T) {
+	result := NewCheckResult("database")
+	result.Status = CheckStatusFail
+	result.Error = "connection refused"
+	result.LastSuccess = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	handler := newHTTPSnapshotHandler(newDiscardLogger(), func() []*ProbeReport {
+		return []*ProbeReport{{
+			Probe:     ProbeNameRediness,
+			Status:    CheckStatusFail,
+			Timestamp: time.Time{},
+			Duration:  0,
+			Checks:    []*CheckResult{result},
+		}}
+	})
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/status/report", nil))
+
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("expected status code %d, got %d", http.StatusOK, recorder.Code)
+	}
+
+	if recorder.Header().Get("Content-Type") != "application/json" {
+		t.Fatalf("unexpected content type: %s", recorder.Header().Get("Content-Type"))
+	}
+
+	reports := make([]*ProbeReport, 0)
+	if err := json.Unmarshal(recorder.Body.Bytes(), &reports); err != nil {
+		t.Fatalf("unable to unmarshal snapshot: %s", err)
+	}
+
+	if len(reports) != 1 || len(reports[0].Checks) != 1 {
+		t.Fatalf("unexpected snapshot: %s", recorder.Body.String())
+	}
+
+	check := reports[0].Checks[0]
+	if check.Status != CheckStatusFail || check.Error != result.Error || !check.LastSuccess.Equal(result.LastSuccess) {
+		t.Fatalf("unexpected check result: %+v", check)
+	}
+}
@@ -0,0 +1,254 @@
+/*
+ *
+ *
+ * MIT NON-AI License
+ *
+ * Copyright (c) 2022-2024 Aleksei Kotelnikov(gudron2s@gmail.com)
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy of the software and associated documentation files (the "Software"),
+ * to deal in the Software without restriction, including without limitation the rights to use, copy, modify, merge, publish, distribute, sublicense,
+ * and/or sell copies of the Software, and to permit persons to whom the Software is furnished to do so, subject to the following conditions.
+ *
+ * The above copyright notice and this permission notice shall be included in all copies or substantial portions of the Software.
+ *
+ * In addition, the following restrictions apply:
+ *
+ * 1. The Software and any modifications made to it may not be used for the purpose of training or improving machine learning algorithms,
+ * including but not limited to artificial intelligence, natural language processing, or data mining. This condition applies to any derivatives,
+ * modifications, or updates based on the Software code. Any usage of the Software in an AI-training dataset is considered a breach of this License.
+ *
+ * 2. The Software may not be included in any dataset used for training or improving machine learning algorithms,
+ * including but not limited to artificial intelligence, natural language processing, or data mining.
+ *
+ * 3. Any person or organization found to be in violation of these restrictions will be subject to legal action and may be held liable
+ * for any damages resulting from such use.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM,
+ * DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE
+ * OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+ *
+ */
+
+package healthcheck
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"log/slog"
+	"os"
+	"sync"
+	"time"
+)
+
+const (
+	tlsReloadCheckInterval = time.Second * 5
+)
+
+var (
+	ErrTLSCertPathNotSet      = errors.New("healthcheck probe tls certificate or key path not set")
+	ErrTLSClientCAParseFailed = errors.New("unable to parse any certificate from client CA bundle")
+)
+
+type tlsFileState struct {
+	modTime time.Time
+	size    int64
+}
+
+// tlsConfigReloader - loads probe server certificate and optional client CA bundle from disk and re-reads
+// them on TLS handshake if files was changed, e.g. rotated by cert-manager or updated kubernetes secret volume
+type tlsConfigReloader struct {
+	l *slog.Logger
+	e errorFormatterService
+
+	certPath     string
+	keyPath      string
+	clientCAPath string
+
+	mu sync.RWMutex
+
+	certificate  *tls.Certificate
+	clientCAPool *x509.CertPool
+	filesState   map[string]tlsFileState
+	lastCheckAt  time.Time
+}
+
+// Load - force load certificate, key and client CA bundle from disk
+func (r *tlsConfigReloader) Load() error {
+	if r.certPath == "" || r.keyPath == "" {
+		return r.e.ErrorOnly(ErrTLSCertPathNotSet)
+	}
+
+	filesState, err := r.readFilesState()
+	if err != nil {
+		return r.e.ErrorOnly(err)
+	}
+
+	certificate, err := tls.LoadX509KeyPair(r.certPath, r.keyPath)
+	if err != nil {
+		return r.e.ErrorOnly(err)
+	}
+
+	var clientCAPool *x509.CertPool
+
+	if r.clientCAPath != "" {
+		caBundle, readErr := os.ReadFile(r.clientCAPath)
+		if readErr != nil {
+			return r.e.ErrorOnly(readErr)
+		}
+
+		clientCAPool = x509.NewCertPool()
+		if !clientCAPool.AppendCertsFromPEM(caBundle) {
+			return r.e.ErrorOnly(ErrTLSClientCAParseFailed, r.clientCAPath)
+		}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.certificate = &certificate
+	r.clientCAPool = clientCAPool
+	r.filesState = filesState
+	r.lastCheckAt = time.Now()
+
+	return nil
+}
+
+// GetTLSConfig - returns base tls.Config for probe http.Server
+func (r *tlsConfigReloader) GetTLSConfig() *tls.Config {
+	//nolint:exhaustruct // it's ok here. we don't need to fully fill up tls.Config struct
+	return &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		GetCertificate:     r.GetCertificate,
+		GetConfigForClient: r.GetConfigForClient,
+	}
+}
+
+func (r *tlsConfigReloader) GetCertificate(_ *tls.ClientHelloInfo) (*tls.Certificate, error) {
+	r.reloadIfChanged()
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.certificate, nil
+}
+
+func (r *tlsConfigReloader) GetConfigForClient(_ *tls.ClientHelloInfo) (*tls.Config, error) {
+	r.reloadIfChanged()
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	//nolint:exhaustruct // it's ok here. we don't need to fully fill up tls.Config struct
+	cfg := &tls.Config{
+		MinVersion:   tls.VersionTLS12,
+		Certificates: []tls.Certificate{*r.certificate},
+		ClientAuth:   tls.NoClientCert,
+	}
+
+	if r.clientCAPool != nil {
+		cfg.ClientAuth = tls.RequireAndVerifyClientCert
+		cfg.ClientCAs = r.clientCAPool
+	}
+
+	return cfg, nil
+}
+
+func (r *tlsConfigReloader) reloadIfChanged() {
+	r.mu.Lock()
+	if time.Since(r.lastCheckAt) < tlsReloadCheckInterval {
+		r.mu.Unlock()
+
+		return
+	}
+
+	r.lastCheckAt = time.Now()
+	prevFilesState := r.filesState
+	r.mu.Unlock()
+
+	filesState, err := r.readFilesState()
+	if err != nil {
+		r.l.Error("unable to read tls files state, keep using previous certificate",
+			slog.Any(ErrorTag, err))
+
+		return
+	}
+
+	if !r.isStateChanged(prevFilesState, filesState) {
+		return
+	}
+
+	err = r.Load()
+	if err != nil {
+		r.l.Error("unable to reload tls certificate, keep using previous certificate",
+			slog.Any(ErrorTag, err))
+
+		return
+	}
+
+	r.l.Info("tls certificate successfully reloaded")
+}
+
+func (r *tlsConfigReloader) readFilesState() (map[string]tlsFileState, error) {
+	paths := []string{r.certPath, r.keyPath}
+	if r.clientCAPath != "" {
+		paths = append(paths, r.clientCAPath)
+	}
+
+	filesState := make(map[string]tlsFileState, len(paths))
+
+	for _, path := range paths {
+		fileInfo, err := os.Stat(path)
+		if err != nil {
+			return nil, r.e.ErrorOnly(err, path)
+		}
+
+		filesState[path] = tlsFileState{
+			modTime: fileInfo.ModTime(),
+			size:    fileInfo.Size(),
+		}
+	}
+
+	return filesState, nil
+}
+
+func (r *tlsConfigReloader) isStateChanged(prev, current map[string]tlsFileState) bool {
+	if len(prev) != len(current) {
+		return true
+	}
+
+	for path, state := range current {
+		prevState, isExists := prev[path]
+		if !isExists {
+			return true
+		}
+
+		if !prevState.modTime.Equal(state.modTime) || prevState.size != state.size {
+			return true
+		}
+	}
+
+	return false
+}
+
+func newTLSConfigReloader(logger *slog.Logger,
+	errFmtSvc errorFormatterService,
+	configSvc *unitConfig,
+) *tlsConfigReloader {
+	return &tlsConfigReloader{
+		l: logger,
+		e: errFmtSvc,
+
+		certPath:     configSvc.GetTLSCertPath(),
+		keyPath:      configSvc.GetTLSKeyPath(),
+		clientCAPath: configSvc.GetTLSClientCAPath(),
+
+		mu: sync.RWMutex{},
+
+		certificate:  nil,
+		clientCAPool: nil,
+		filesState:   nil,
+		lastCheckAt:  time.Time{},
+	}
+}
@@ -0,0 +1,392 @@
+/*
+ *
+ *
+ * MIT NON-AI License
+ *
+ * Copyright (c) 2022-2024 Aleksei Kotelnikov(gudron2s@gmail.com)
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy of the software and associated documentation files (the "Software"),
+ * to deal in the Software without restriction, including without limitation the rights to use, copy, modify, merge, publish, distribute, sublicense,
+ * and/or sell copies of the Software, and to permit persons to whom the Software is furnished to do so, subject to the following conditions.
+ *
+ * The above copyright notice and this permission notice shall be included in all copies or substantial portions of the Software.
+ *
+ * In addition, the following restrictions apply:
+ *
+ * 1. The Software and any modifications made to it may not be used for the purpose of training or improving machine learning algorithms,
+ * including but not limited to artificial intelligence, natural language processing, or data mining. This condition applies to any derivatives,
+ * modifications, or updates based on the Software code. Any usage of the Software in an AI-training dataset is considered a breach of this License.
+ *
+ * 2. The Software may not be included in any dataset used for training or improving machine learning algorithms,
+ * including but not limited to artificial intelligence, natural language processing, or data mining.
+ *
+ * 3. Any person or organization found to be in violation of these restrictions will be subject to legal action and may be held liable
+ * for any damages resulting from such use.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM,
+ * DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE
+ * OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+ *
+ */
+package healthcheck
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"log/slog"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// fakeErrorFormatter - error formatter service of tests, wraps errors with details by fmt package
+type fakeErrorFormatter struct{}
+
+func (f *fakeErrorFormatter) ErrorWithCode(err error, _ int) error {
+	return err
+}
+
+func (f *fakeErrorFormatter) ErrWithCode(err error, _ int) error {
+	return err
+}
+
+func (f *fakeErrorFormatter) ErrorGetCode(_ error) int {
+	return 0
+}
+
+func (f *fakeErrorFormatter) ErrGetCode(_ error) int {
+	return 0
+}
+
+func (f *fakeErrorFormatter) ErrorNoWrap(err error) error {
+	return err
+}
+
+func (f *fakeErrorFormatter) ErrNoWrap(err error) error {
+	return err
+}
+
+func (f *fakeErrorFormatter) ErrorOnly(err error, details ...string) error {
+	return f.Error(err, details...)
+}
+
+func (f *fakeErrorFormatter) Error(err error, details ...string) error {
+	if len(details) == 0 {
+		return err
+	}
+
+	return fmt.Errorf("%w: %v", err, details)
+}
+
+func (f *fakeErrorFormatter) Errorf(err error, format string, args ...interface{}) error {
+	return fmt.Errorf("%w: %s", err, fmt.Sprintf(format, args...))
+}
+
+func (f *fakeErrorFormatter) NewError(details ...string) error {
+	return fmt.Errorf("%v", details) //nolint:err113 // test
+}
+
+func (f *fakeErrorFormatter) NewErrorf(format string, args ...interface{}) error {
+	return fmt.Errorf(format, args...) //nolint:err113 // test
+}
+
+func newDiscardLogger() *slog.Logger {
+	return slog.New(slog.NewTextHandler(io.Discard, nil))
+}
+
+// testCertificate - certificate and key generated by test, signed by parent or self-signed
+type testCertificate struct {
+	cert    *x509.Certificate
+	key     *ecdsa.PrivateKey
+	certPEM []byte
+	keyPEM  []byte
+}
+
+func newTestCertificate(t *testing.T, commonName string, parent *testCertificate) *testCertificate {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("unable to generate key: %s", err)
+	}
+
+	serial, err := rand.Int(rand.Reader, big.NewInt(1<<62))
+	if err != nil {
+		t.Fatalf("unable to generate serial: %s", err)
+	}
+
+	//nolint:exhaustruct // only required fields of certificate
+	template := &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      pkix.Name{CommonName: commonName},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		IPAddresses:  []net.IP{net.IPv4(127, 0, 0, 1)},
+
+		BasicConstraintsValid: true,
+		IsCA:                  parent == nil,
+	}
+
+	parentCert, parentKey := template, key
+	if parent != nil {
+		parentCert, parentKey = parent.cert, parent.key
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, parentCert, &key.PublicKey, parentKey)
+	if err != nil {
+		t.Fatalf("unable to create certificate: %s", err)
+	}
+
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatalf("unable to parse certificate: %s", err)
+	}
+
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatalf("unable to marshal key: %s", err)
+	}
+
+	return &testCertificate{
+		cert:    cert,
+		key:     key,
+		certPEM: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Headers: nil, Bytes: der}),
+		keyPEM:  pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Headers: nil, Bytes: keyDER}),
+	}
+}
+
+func (c *testCertificate) tlsCertificate() tls.Certificate {
+	//nolint:exhaustruct // only certificate chain and key
+	return tls.Certificate{
+		Certificate: [][]byte{c.cert.Raw},
+		PrivateKey:  c.key,
+	}
+}
+
+func writeTestFile(t *testing.T, path string, content []byte, modTime time.Time) {
+	t.Helper()
+
+	err := os.WriteFile(path, content, 0o600)
+	if err != nil {
+		t.Fatalf("unable to write %s: %s", path, err)
+	}
+
+	err = os.Chtimes(path, modTime, modTime)
+	if err != nil {
+		t.Fatalf("unable to change times of %s: %s", path, err)
+	}
+}
+
+func newTestTLSConfigReloader(certPath, keyPath, clientCAPath string) *tlsConfigReloader {
+	//nolint:exhaustruct // only tls params of probe
+	return newTLSConfigReloader(newDiscardLogger(), &fakeErrorFormatter{}, &unitConfig{
+		TLSEnabled:      true,
+		TLSCertPath:     certPath,
+		TLSKeyPath:      keyPath,
+		TLSClientCAPath: clientCAPath,
+	})
+}
+
+func TestTLSConfigReloader_Load(t *testing.T) {
+	dir := t.TempDir()
+	certPath := filepath.Join(dir, "tls.crt")
+	keyPath := filepath.Join(dir, "tls.key")
+
+	reloader := newTestTLSConfigReloader("", "", "")
+
+	err := reloader.Load()
+	if !errors.Is(err, ErrTLSCertPathNotSet) {
+		t.Fatalf("expected %s, got %v", ErrTLSCertPathNotSet, err)
+	}
+
+	reloader = newTestTLSConfigReloader(certPath, keyPath, "")
+
+	err = reloader.Load()
+	if !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("expected %s, got %v", os.ErrNotExist, err)
+	}
+
+	serverCert := newTestCertificate(t, "server", nil)
+	writeTestFile(t, certPath, serverCert.certPEM, time.Now())
+	writeTestFile(t, keyPath, serverCert.keyPEM, time.Now())
+
+	caPath := filepath.Join(dir, "ca.crt")
+	writeTestFile(t, caPath, []byte("not a certificate"), time.Now())
+
+	reloader = newTestTLSConfigReloader(certPath, keyPath, caPath)
+
+	err = reloader.Load()
+	if !errors.Is(err, ErrTLSClientCAParseFailed) {
+		t.Fatalf("expected %s, got %v", ErrTLSClientCAParseFailed, err)
+	}
+}
+
+func TestTLSConfigReloader_GetCertificate(t *testing.T) {
+	dir := t.TempDir()
+	certPath := filepath.Join(dir, "tls.crt")
+	keyPath := filepath.Join(dir, "tls.key")
+
+	issuedAt := time.Now().Add(-time.Hour)
+
+	firstCert := newTestCertificate(t, "first", nil)
+	writeTestFile(t, certPath, firstCert.certPEM, issuedAt)
+	writeTestFile(t, keyPath, firstCert.keyPEM, issuedAt)
+
+	reloader := newTestTLSConfigReloader(certPath, keyPath, "")
+
+	err := reloader.Load()
+	if err != nil {
+		t.Fatalf("unable to load certificate: %s", err)
+	}
+
+	assertServedCertificate := func(expected *testCertificate) {
+		t.Helper()
+
+		certificate, getErr := reloader.GetCertificate(nil)
+		if getErr != nil {
+			t.Fatalf("unable to get certificate: %s", getErr)
+		}
+
+		if !expected.cert.Equal(mustParseLeaf(t, certificate)) {
+			t.Fatalf("expected certificate %s", expected.cert.Subject.CommonName)
+		}
+	}
+
+	assertServedCertificate(firstCert)
+
+	// rotation of certificate, e.g. by cert-manager
+	rotatedCert := newTestCertificate(t, "rotated", nil)
+	writeTestFile(t, certPath, rotatedCert.certPEM, issuedAt.Add(time.Minute))
+	writeTestFile(t, keyPath, rotatedCert.keyPEM, issuedAt.Add(time.Minute))
+
+	// files state re-checked not often than once per reload check interval
+	assertServedCertificate(firstCert)
+
+	reloader.lastCheckAt = time.Now().Add(-tlsReloadCheckInterval)
+
+	assertServedCertificate(rotatedCert)
+
+	// broken certificate is not applied, previous certificate kept
+	writeTestFile(t, certPath, []byte("broken"), issuedAt.Add(2*time.Minute))
+	reloader.lastCheckAt = time.Now().Add(-tlsReloadCheckInterval)
+
+	assertServedCertificate(rotatedCert)
+}
+
+func mustParseLeaf(t *testing.T, certificate *tls.Certificate) *x509.Certificate {
+	t.Helper()
+
+	if certificate == nil || len(certificate.Certificate) == 0 {
+		t.Fatal("empty certificate")
+	}
+
+	leaf, err := x509.ParseCertificate(certificate.Certificate[0])
+	if err != nil {
+		t.Fatalf("unable to parse certificate: %s", err)
+	}
+
+	return leaf
+}
+
+func TestTLSConfigReloader_ClientCA(t *testing.T) {
+	dir := t.TempDir()
+	certPath := filepath.Join(dir, "tls.crt")
+	keyPath := filepath.Join(dir, "tls.key")
+	caPath := filepath.Join(dir, "ca.crt")
+
+	ca := newTestCertificate(t, "ca", nil)
+	serverCert := newTestCertificate(t, "server", ca)
+	clientCert := newTestCertificate(t, "client", ca)
+	untrustedClientCert := newTestCertificate(t, "untrusted", nil)
+
+	writeTestFile(t, certPath, serverCert.certPEM, time.Now())
+	writeTestFile(t, keyPath, serverCert.keyPEM, time.Now())
+	writeTestFile(t, caPath, ca.certPEM, time.Now())
+
+	reloader := newTestTLSConfigReloader(certPath, keyPath, caPath)
+
+	err := reloader.Load()
+	if err != nil {
+		t.Fatalf("unable to load certificate: %s", err)
+	}
+
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	// handshake errors of rejected clients are expected
+	server.Config.ErrorLog = log.New(io.Discard, "", 0)
+	server.TLS = reloader.GetTLSConfig()
+	server.StartTLS()
+	t.Cleanup(server.Close)
+
+	rootCAs := x509.NewCertPool()
+	rootCAs.AddCert(ca.cert)
+
+	testCases := []struct {
+		name         string
+		certificates []tls.Certificate
+		expectedErr  bool
+	}{
+		{
+			name:         "client without certificate",
+			certificates: nil,
+			expectedErr:  true,
+		},
+		{
+			name:         "client with certificate of unknown authority",
+			certificates: []tls.Certificate{untrustedClientCert.tlsCertificate()},
+			expectedErr:  true,
+		},
+		{
+			name:         "client with certificate signed by CA",
+			certificates: []tls.Certificate{clientCert.tlsCertificate()},
+			expectedErr:  false,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			//nolint:exhaustruct // only tls client config
+			client := &http.Client{
+				Timeout: 5 * time.Second,
+				Transport: &http.Transport{
+					TLSClientConfig: &tls.Config{
+						MinVersion:   tls.VersionTLS12,
+						RootCAs:      rootCAs,
+						Certificates: testCase.certificates,
+					},
+				},
+			}
+
+			resp, reqErr := client.Get(server.URL) //nolint:noctx // test
+			if resp != nil {
+				_ = resp.Body.Close()
+			}
+
+			if testCase.expectedErr && reqErr == nil {
+				t.Fatal("expected rejection of client")
+			}
+
+			if !testCase.expectedErr && reqErr != nil {
+				t.Fatalf("unexpected error: %s", reqErr)
+			}
+		})
+	}
+}
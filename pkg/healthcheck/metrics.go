@@ -0,0 +1,136 @@
+/*
+ *
+ *
+ * MIT NON-AI License
+ *
+ * Copyright (c) 2022-2024 Aleksei Kotelnikov(gudron2s@gmail.com)
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy of the software and associated documentation files (the "Software"),
+ * to deal in the Software without restriction, including without limitation the rights to use, copy, modify, merge, publish, distribute, sublicense,
+ * and/or sell copies of the Software, and to permit persons to whom the Software is furnished to do so, subject to the following conditions.
+ *
+ * The above copyright notice and this permission notice shall be included in all copies or substantial portions of the Software.
+ *
+ * In addition, the following restrictions apply:
+ *
+ * 1. The Software and any modifications made to it may not be used for the purpose of training or improving machine learning algorithms,
+ * including but not limited to artificial intelligence, natural language processing, or data mining. This condition applies to any derivatives,
+ * modifications, or updates based on the Software code. Any usage of the Software in an AI-training dataset is considered a breach of this License.
+ *
+ * 2. The Software may not be included in any dataset used for training or improving machine learning algorithms,
+ * including but not limited to artificial intelligence, natural language processing, or data mining.
+ *
+ * 3. Any person or organization found to be in violation of these restrictions will be subject to legal action and may be held liable
+ * for any damages resulting from such use.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM,
+ * DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE
+ * OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+ *
+ */
+
+package healthcheck
+
+import (
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+)
+
+const (
+	metricsContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+	checkStatusWarnMetricValue = 0.5
+)
+
+const (
+	metricsLabelProbe      = "probe"
+	metricsLabelCheck      = "check"
+	metricsLabelFromStatus = "from"
+	metricsLabelToStatus   = "to"
+	metricsLabelCode       = "code"
+)
+
+// healthMetrics - metrics of probes and check units in prometheus text exposition format
+type healthMetrics struct {
+	l *slog.Logger
+
+	registry *metricsRegistry
+
+	checkStatus              *metricFamily
+	checkDuration            *metricFamily
+	checkConsecutiveFailures *metricFamily
+	checkTransitions         *metricFamily
+	probeRequests            *metricFamily
+}
+
+// OnCheckResult - implementation of checkResultObserver interface
+func (m *healthMetrics) OnCheckResult(probeName string, prevResult, result *CheckResult) {
+	m.checkStatus.Set(checkStatusMetricValue(result.Status), probeName, result.Name)
+	m.checkDuration.Observe(result.Duration.Seconds(), probeName, result.Name)
+	m.checkConsecutiveFailures.Set(float64(result.ConsecutiveFailures), probeName, result.Name)
+
+	if prevResult != nil && prevResult.Status != result.Status {
+		m.checkTransitions.Inc(probeName, result.Name, string(prevResult.Status), string(result.Status))
+	}
+}
+
+func (m *healthMetrics) OnProbeRequest(probeName string, statusCode int) {
+	m.probeRequests.Inc(probeName, strconv.Itoa(statusCode))
+}
+
+func (m *healthMetrics) Encode(writer io.Writer) error {
+	return m.registry.Encode(writer)
+}
+
+func (m *healthMetrics) ServeHTTP(respWriter http.ResponseWriter, _ *http.Request) {
+	respWriter.Header().Add("Content-Type", metricsContentType)
+	respWriter.WriteHeader(http.StatusOK)
+
+	err := m.registry.Encode(respWriter)
+	if err != nil {
+		m.l.Error("unable to write metrics response", slog.Any(ErrorTag, err))
+	}
+}
+
+func checkStatusMetricValue(status CheckStatus) float64 {
+	switch status {
+	case CheckStatusPass:
+		return 1
+	case CheckStatusWarn:
+		return checkStatusWarnMetricValue
+	case CheckStatusFail, CheckStatusSkipped:
+		return 0
+	default:
+		return 0
+	}
+}
+
+func newHealthMetrics(logger *slog.Logger) *healthMetrics {
+	registry := newMetricsRegistry()
+
+	return &healthMetrics{
+		l: logger,
+
+		registry: registry,
+
+		checkStatus: registry.Register(newMetricFamily("healthcheck_check_status",
+			"Status of last check unit execution: 1 - pass, 0.5 - warn, 0 - fail or skipped.",
+			metricTypeGauge, nil, metricsLabelProbe, metricsLabelCheck)),
+		checkDuration: registry.Register(newMetricFamily("healthcheck_check_duration_seconds",
+			"Duration of check unit execution in seconds.",
+			metricTypeHistogram, defaultDurationBuckets, metricsLabelProbe, metricsLabelCheck)),
+		checkConsecutiveFailures: registry.Register(newMetricFamily("healthcheck_check_consecutive_failures",
+			"Count of consecutive failed executions of check unit, reset to zero on success.",
+			metricTypeGauge, nil, metricsLabelProbe, metricsLabelCheck)),
+		checkTransitions: registry.Register(newMetricFamily("healthcheck_check_transitions_total",
+			"Count of check unit status transitions.",
+			metricTypeCounter, nil, metricsLabelProbe, metricsLabelCheck,
+			metricsLabelFromStatus, metricsLabelToStatus)),
+		probeRequests: registry.Register(newMetricFamily("healthcheck_probe_requests_total",
+			"Count of probe http requests by response status code.",
+			metricTypeCounter, nil, metricsLabelProbe, metricsLabelCode)),
+	}
+}
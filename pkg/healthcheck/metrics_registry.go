@@ -0,0 +1,281 @@
+/*
+ *
+ *
+ * MIT NON-AI License
+ *
+ * Copyright (c) 2022-2024 Aleksei Kotelnikov(gudron2s@gmail.com)
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy of the software and associated documentation files (the "Software"),
+ * to deal in the Software without restriction, including without limitation the rights to use, copy, modify, merge, publish, distribute, sublicense,
+ * and/or sell copies of the Software, and to permit persons to whom the Software is furnished to do so, subject to the following conditions.
+ *
+ * The above copyright notice and this permission notice shall be included in all copies or substantial portions of the Software.
+ *
+ * In addition, the following restrictions apply:
+ *
+ * 1. The Software and any modifications made to it may not be used for the purpose of training or improving machine learning algorithms,
+ * including but not limited to artificial intelligence, natural language processing, or data mining. This condition applies to any derivatives,
+ * modifications, or updates based on the Software code. Any usage of the Software in an AI-training dataset is considered a breach of this License.
+ *
+ * 2. The Software may not be included in any dataset used for training or improving machine learning algorithms,
+ * including but not limited to artificial intelligence, natural language processing, or data mining.
+ *
+ * 3. Any person or organization found to be in violation of these restrictions will be subject to legal action and may be held liable
+ * for any damages resulting from such use.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM,
+ * DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE
+ * OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+ *
+ */
+
+package healthcheck
+
+import (
+	"bufio"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+type metricType string
+
+const (
+	metricTypeCounter   metricType = "counter"
+	metricTypeGauge     metricType = "gauge"
+	metricTypeHistogram metricType = "histogram"
+)
+
+const (
+	labelValuesSeparator = "\xff"
+)
+
+//nolint:gochecknoglobals // it's ok here, same with default buckets of prometheus client
+var defaultDurationBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+
+type metricSeries struct {
+	labelValues []string
+
+	value float64
+
+	bucketCounts []uint64
+	sum          float64
+	count        uint64
+}
+
+// metricFamily - minimal implementation of prometheus metric with labels.
+// Used instead of prometheus client library for keep library dependency free
+type metricFamily struct {
+	name       string
+	help       string
+	metricType metricType
+
+	labelNames []string
+	buckets    []float64
+
+	series map[string]*metricSeries
+
+	mu sync.Mutex
+}
+
+func (f *metricFamily) Set(value float64, labelValues ...string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.getSeries(labelValues).value = value
+}
+
+func (f *metricFamily) Add(value float64, labelValues ...string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.getSeries(labelValues).value += value
+}
+
+func (f *metricFamily) Inc(labelValues ...string) {
+	f.Add(1, labelValues...)
+}
+
+func (f *metricFamily) Observe(value float64, labelValues ...string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	series := f.getSeries(labelValues)
+
+	for i, upperBound := range f.buckets {
+		if value <= upperBound {
+			series.bucketCounts[i]++
+		}
+	}
+
+	series.sum += value
+	series.count++
+}
+
+func (f *metricFamily) getSeries(labelValues []string) *metricSeries {
+	key := strings.Join(labelValues, labelValuesSeparator)
+
+	series, isExists := f.series[key]
+	if !isExists {
+		series = &metricSeries{
+			labelValues:  append([]string(nil), labelValues...),
+			value:        0,
+			bucketCounts: make([]uint64, len(f.buckets)),
+			sum:          0,
+			count:        0,
+		}
+
+		f.series[key] = series
+	}
+
+	return series
+}
+
+// Encode - write metric family in prometheus text exposition format
+func (f *metricFamily) Encode(writer *bufio.Writer) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if len(f.series) == 0 {
+		return
+	}
+
+	_, _ = writer.WriteString("# HELP " + f.name + " " + escapeMetricHelp(f.help) + "\n")
+	_, _ = writer.WriteString("# TYPE " + f.name + " " + string(f.metricType) + "\n")
+
+	keys := make([]string, 0, len(f.series))
+	for key := range f.series {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		series := f.series[key]
+
+		if f.metricType != metricTypeHistogram {
+			f.writeSample(writer, f.name, series.labelValues, "", "", series.value)
+
+			continue
+		}
+
+		for i, upperBound := range f.buckets {
+			f.writeSample(writer, f.name+"_bucket", series.labelValues,
+				"le", formatMetricValue(upperBound), float64(series.bucketCounts[i]))
+		}
+
+		f.writeSample(writer, f.name+"_bucket", series.labelValues,
+			"le", "+Inf", float64(series.count))
+		f.writeSample(writer, f.name+"_sum", series.labelValues, "", "", series.sum)
+		f.writeSample(writer, f.name+"_count", series.labelValues, "", "", float64(series.count))
+	}
+}
+
+func (f *metricFamily) writeSample(writer *bufio.Writer,
+	name string,
+	labelValues []string,
+	extraLabelName, extraLabelValue string,
+	value float64,
+) {
+	_, _ = writer.WriteString(name)
+
+	labelsCount := len(labelValues)
+	if extraLabelName != "" {
+		labelsCount++
+	}
+
+	if labelsCount > 0 {
+		_ = writer.WriteByte('{')
+
+		for i, labelValue := range labelValues {
+			if i > 0 {
+				_ = writer.WriteByte(',')
+			}
+
+			_, _ = writer.WriteString(f.labelNames[i] + `="` + escapeMetricLabelValue(labelValue) + `"`)
+		}
+
+		if extraLabelName != "" {
+			if len(labelValues) > 0 {
+				_ = writer.WriteByte(',')
+			}
+
+			_, _ = writer.WriteString(extraLabelName + `="` + extraLabelValue + `"`)
+		}
+
+		_ = writer.WriteByte('}')
+	}
+
+	_, _ = writer.WriteString(" " + formatMetricValue(value) + "\n")
+}
+
+func newMetricFamily(name, help string,
+	metricType metricType,
+	buckets []float64,
+	labelNames ...string,
+) *metricFamily {
+	return &metricFamily{
+		name:       name,
+		help:       help,
+		metricType: metricType,
+
+		labelNames: labelNames,
+		buckets:    buckets,
+
+		series: make(map[string]*metricSeries),
+
+		mu: sync.Mutex{},
+	}
+}
+
+// metricsRegistry - ordered list of metric families
+type metricsRegistry struct {
+	families []*metricFamily
+}
+
+func (r *metricsRegistry) Register(family *metricFamily) *metricFamily {
+	r.families = append(r.families, family)
+
+	return family
+}
+
+func (r *metricsRegistry) Encode(writer io.Writer) error {
+	bufWriter := bufio.NewWriter(writer)
+
+	for _, family := range r.families {
+		family.Encode(bufWriter)
+	}
+
+	return bufWriter.Flush()
+}
+
+func newMetricsRegistry() *metricsRegistry {
+	return &metricsRegistry{
+		families: nil,
+	}
+}
+
+func formatMetricValue(value float64) string {
+	switch {
+	case math.IsInf(value, 1):
+		return "+Inf"
+	case math.IsInf(value, -1):
+		return "-Inf"
+	case math.IsNaN(value):
+		return "NaN"
+	default:
+		return strconv.FormatFloat(value, 'g', -1, 64)
+	}
+}
+
+func escapeMetricHelp(help string) string {
+	return strings.NewReplacer(`\`, `\\`, "\n", `\n`).Replace(help)
+}
+
+func escapeMetricLabelValue(value string) string {
+	return strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`).Replace(value)
+}
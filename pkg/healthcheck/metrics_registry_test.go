@@ -0,0 +1,122 @@
+/*
+ *
+ *
+ * MIT NON-AI License
+ *
+ * Copyright (c) 2022-2024 Aleksei Kotelnikov(gudron2s@gmail.com)
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy of the software and associated documentation files (the "Software"),
+ * to deal in the Software without restriction, including without limitation the rights to use, copy, modify, merge, publish, distribute, sublicense,
+ * and/or sell copies of the Software, and to permit persons to whom the Software is furnished to do so, subject to the following conditions.
+ *
+ * The above copyright notice and this permission notice shall be included in all copies or substantial portions of the Software.
+ *
+ * In addition, the following restrictions apply:
+ *
+ * 1. The Software and any modifications made to it may not be used for the purpose of training or improving machine learning algorithms,
+ * including but not limited to artificial intelligence, natural language processing, or data mining. This condition applies to any derivatives,
+ * modifications, or updates based on the Software code. Any usage of the Software in an AI-training dataset is considered a breach of this License.
+ *
+ * 2. The Software may not be included in any dataset used for training or improving machine learning algorithms,
+ * including but not limited to artificial intelligence, natural language processing, or data mining.
+ *
+ * 3. Any person or organization found to be in violation of these restrictions will be subject to legal action and may be held liable
+ * for any damages resulting from such use.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM,
+ * DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE
+ * OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+ *
+ */
+package healthcheck
+
+import (
+	"bytes"
+	"math"
+	"testing"
+)
+
+const expectedMetricsRegistryOutput = `# HELP healthcheck_check_status Status of check unit.
+# TYPE healthcheck_check_status gauge
+healthcheck_check_status{probe="liveness",check="cache"} 0
+healthcheck_check_status{probe="liveness",check="data\\base \"main\"\nreplica"} 1
+healthcheck_check_status{probe="readiness",check="cache"} 1
+# HELP healthcheck_checks_total Count of check units executions.\nHelp with \\ backslash.
+# TYPE healthcheck_checks_total counter
+healthcheck_checks_total 3
+# HELP healthcheck_check_duration_seconds Duration of check unit execution.
+# TYPE healthcheck_check_duration_seconds histogram
+healthcheck_check_duration_seconds_bucket{check="cache",le="0.1"} 1
+healthcheck_check_duration_seconds_bucket{check="cache",le="1"} 1
+healthcheck_check_duration_seconds_bucket{check="cache",le="+Inf"} 1
+healthcheck_check_duration_seconds_sum{check="cache"} 0.05
+healthcheck_check_duration_seconds_count{check="cache"} 1
+healthcheck_check_duration_seconds_bucket{check="database",le="0.1"} 0
+healthcheck_check_duration_seconds_bucket{check="database",le="1"} 1
+healthcheck_check_duration_seconds_bucket{check="database",le="+Inf"} 2
+healthcheck_check_duration_seconds_sum{check="database"} 2.5
+healthcheck_check_duration_seconds_count{check="database"} 2
+`
+
+func TestMetricsRegistry_Encode(t *testing.T) {
+	registry := newMetricsRegistry()
+
+	statusGauge := registry.Register(newMetricFamily("healthcheck_check_status",
+		"Status of check unit.", metricTypeGauge, nil, "probe", "check"))
+	checksCounter := registry.Register(newMetricFamily("healthcheck_checks_total",
+		"Count of check units executions.\nHelp with \\ backslash.", metricTypeCounter, nil))
+	durationHistogram := registry.Register(newMetricFamily("healthcheck_check_duration_seconds",
+		"Duration of check unit execution.", metricTypeHistogram, []float64{0.1, 1}, "check"))
+	// families without series are not encoded
+	registry.Register(newMetricFamily("healthcheck_unused", "Unused metric.", metricTypeGauge, nil))
+
+	// series added in reverse order, encoder must sort them
+	statusGauge.Set(1, "readiness", "cache")
+	statusGauge.Set(1, "liveness", "data\\base \"main\"\nreplica")
+	statusGauge.Set(0, "liveness", "cache")
+
+	checksCounter.Inc()
+	checksCounter.Add(2)
+
+	durationHistogram.Observe(2, "database")
+	durationHistogram.Observe(0.5, "database")
+	durationHistogram.Observe(0.05, "cache")
+
+	for i := 0; i < 2; i++ {
+		buffer := &bytes.Buffer{}
+
+		if err := registry.Encode(buffer); err != nil {
+			t.Fatalf("unable to encode metrics: %s", err)
+		}
+
+		if buffer.String() != expectedMetricsRegistryOutput {
+			t.Fatalf("unexpected metrics output of %d encoding:\n%s\nexpected:\n%s",
+				i+1, buffer.String(), expectedMetricsRegistryOutput)
+		}
+	}
+}
+
+func TestFormatMetricValue(t *testing.T) {
+	testCases := []struct {
+		name     string
+		value    float64
+		expected string
+	}{
+		{name: "integer", value: 42, expected: "42"},
+		{name: "fraction", value: 0.005, expected: "0.005"},
+		{name: "large value", value: 1e21, expected: "1e+21"},
+		{name: "positive infinity", value: math.Inf(1), expected: "+Inf"},
+		{name: "negative infinity", value: math.Inf(-1), expected: "-Inf"},
+		{name: "not a number", value: math.NaN(), expected: "NaN"},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			formatted := formatMetricValue(testCase.value)
+			if formatted != testCase.expected {
+				t.Fatalf("expected %q, got %q", testCase.expected, formatted)
+			}
+		})
+	}
+}
@@ -0,0 +1,18 @@
+module github.com/crypto-bundle/bc-wallet-common-lib-healthcheck/pkg/healthcheck/otelhealth
+
+go 1.22
+
+require (
+	github.com/crypto-bundle/bc-wallet-common-lib-healthcheck v0.0.8-0.20261019131603-99be00135098
+	go.opentelemetry.io/otel v1.32.0
+	go.opentelemetry.io/otel/sdk v1.32.0
+	go.opentelemetry.io/otel/trace v1.32.0
+)
+
+require (
+	github.com/go-logr/logr v1.4.2 // indirect
+	github.com/go-logr/stdr v1.2.2 // indirect
+	github.com/google/uuid v1.6.0 // indirect
+	go.opentelemetry.io/otel/metric v1.32.0 // indirect
+	golang.org/x/sys v0.28.0 // indirect
+)
@@ -0,0 +1,129 @@
+/*
+ *
+ *
+ * MIT NON-AI License
+ *
+ * Copyright (c) 2022-2024 Aleksei Kotelnikov(gudron2s@gmail.com)
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy of the software and associated documentation files (the "Software"),
+ * to deal in the Software without restriction, including without limitation the rights to use, copy, modify, merge, publish, distribute, sublicense,
+ * and/or sell copies of the Software, and to permit persons to whom the Software is furnished to do so, subject to the following conditions.
+ *
+ * The above copyright notice and this permission notice shall be included in all copies or substantial portions of the Software.
+ *
+ * In addition, the following restrictions apply:
+ *
+ * 1. The Software and any modifications made to it may not be used for the purpose of training or improving machine learning algorithms,
+ * including but not limited to artificial intelligence, natural language processing, or data mining. This condition applies to any derivatives,
+ * modifications, or updates based on the Software code. Any usage of the Software in an AI-training dataset is considered a breach of this License.
+ *
+ * 2. The Software may not be included in any dataset used for training or improving machine learning algorithms,
+ * including but not limited to artificial intelligence, natural language processing, or data mining.
+ *
+ * 3. Any person or organization found to be in violation of these restrictions will be subject to legal action and may be held liable
+ * for any damages resulting from such use.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM,
+ * DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE
+ * OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+ *
+ */
+
+// Package otelhealth - OpenTelemetry tracer of probe requests and check units execution.
+// Separate module, so OpenTelemetry dependency required only by applications which use it
+package otelhealth
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/crypto-bundle/bc-wallet-common-lib-healthcheck/pkg/healthcheck"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	tracerInstrumentationName = "github.com/crypto-bundle/bc-wallet-common-lib-healthcheck/pkg/healthcheck"
+
+	ProbeSpanName = "healthcheck.probe"
+	CheckSpanName = "healthcheck.check"
+)
+
+const (
+	ProbeTypeAttr     = attribute.Key("healthcheck.probe.type")
+	StatusCodeAttr    = attribute.Key("http.response.status_code")
+	CheckNameAttr     = attribute.Key("healthcheck.check.name")
+	CheckStatusAttr   = attribute.Key("healthcheck.check.status")
+	CheckDurationAttr = attribute.Key("healthcheck.check.duration_ms")
+	CheckErrorAttr    = attribute.Key("healthcheck.check.error")
+)
+
+// tracer - creates span per probe request and child span per check unit.
+// Span context of probe request extracted from request headers by global text map propagator
+type tracer struct {
+	tracer trace.Tracer
+}
+
+func (t *tracer) StartProbeSpan(httpReq *http.Request, probeName string) (context.Context, healthcheck.ProbeSpan) {
+	ctx := otel.GetTextMapPropagator().Extract(httpReq.Context(), propagation.HeaderCarrier(httpReq.Header))
+
+	ctx, span := t.tracer.Start(ctx, ProbeSpanName,
+		trace.WithSpanKind(trace.SpanKindServer),
+		trace.WithAttributes(ProbeTypeAttr.String(probeName)))
+
+	return ctx, &probeSpan{span: span}
+}
+
+func (t *tracer) StartCheckSpan(ctx context.Context,
+	probeName string,
+	checkName string,
+) (context.Context, healthcheck.CheckSpan) {
+	ctx, span := t.tracer.Start(ctx, CheckSpanName,
+		trace.WithSpanKind(trace.SpanKindInternal),
+		trace.WithAttributes(ProbeTypeAttr.String(probeName),
+			CheckNameAttr.String(checkName)))
+
+	return ctx, &checkSpan{span: span}
+}
+
+type probeSpan struct {
+	span trace.Span
+}
+
+func (s *probeSpan) End(statusCode int) {
+	s.span.SetAttributes(StatusCodeAttr.Int(statusCode))
+
+	s.span.End()
+}
+
+type checkSpan struct {
+	span trace.Span
+}
+
+func (s *checkSpan) End(result *healthcheck.CheckResult) {
+	s.span.SetAttributes(CheckStatusAttr.String(string(result.Status)),
+		CheckDurationAttr.Float64(float64(result.Duration)/float64(time.Millisecond)))
+
+	if result.Error != "" {
+		s.span.SetAttributes(CheckErrorAttr.String(result.Error))
+	}
+
+	if !result.IsHealthy() {
+		s.span.SetStatus(codes.Error, result.Error)
+	}
+
+	s.span.End()
+}
+
+// NewTracer - returns tracer of probe requests and check units execution, which uses tracer of provider
+func NewTracer(provider trace.TracerProvider) *tracer {
+	return &tracer{
+		tracer: provider.Tracer(tracerInstrumentationName),
+	}
+}
@@ -0,0 +1,255 @@
+/*
+ *
+ *
+ * MIT NON-AI License
+ *
+ * Copyright (c) 2022-2024 Aleksei Kotelnikov(gudron2s@gmail.com)
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy of the software and associated documentation files (the "Software"),
+ * to deal in the Software without restriction, including without limitation the rights to use, copy, modify, merge, publish, distribute, sublicense,
+ * and/or sell copies of the Software, and to permit persons to whom the Software is furnished to do so, subject to the following conditions.
+ *
+ * The above copyright notice and this permission notice shall be included in all copies or substantial portions of the Software.
+ *
+ * In addition, the following restrictions apply:
+ *
+ * 1. The Software and any modifications made to it may not be used for the purpose of training or improving machine learning algorithms,
+ * including but not limited to artificial intelligence, natural language processing, or data mining. This condition applies to any derivatives,
+ * modifications, or updates based on the Software code. Any usage of the Software in an AI-training dataset is considered a breach of this License.
+ *
+ * 2. The Software may not be included in any dataset used for training or improving machine learning algorithms,
+ * including but not limited to artificial intelligence, natural language processing, or data mining.
+ *
+ * 3. Any person or organization found to be in violation of these restrictions will be subject to legal action and may be held liable
+ * for any damages resulting from such use.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM,
+ * DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE
+ * OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+ *
+ */
+
+package otelhealth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/crypto-bundle/bc-wallet-common-lib-healthcheck/pkg/healthcheck"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+type testLogger struct{}
+
+func (testLogger) NewStdLoggerEntry(_ ...any) *log.Logger { return log.New(io.Discard, "", 0) }
+func (testLogger) NewStdNamedLoggerEntry(_ string, _ ...any) *log.Logger {
+	return log.New(io.Discard, "", 0)
+}
+func (testLogger) NewSlogLoggerEntry(_ ...any) *slog.Logger { return testSlogLogger() }
+func (testLogger) NewSlogNamedLoggerEntry(_ string, _ ...any) *slog.Logger {
+	return testSlogLogger()
+}
+func (testLogger) NewSlogLoggerEntryWithFields(_ ...slog.Attr) *slog.Logger { return testSlogLogger() }
+
+func testSlogLogger() *slog.Logger {
+	return slog.New(slog.NewTextHandler(io.Discard, nil))
+}
+
+type testErrorFormatter struct{}
+
+func (testErrorFormatter) ErrorWithCode(err error, _ int) error       { return err }
+func (testErrorFormatter) ErrWithCode(err error, _ int) error         { return err }
+func (testErrorFormatter) ErrorGetCode(_ error) int                   { return 0 }
+func (testErrorFormatter) ErrGetCode(_ error) int                     { return 0 }
+func (testErrorFormatter) ErrorNoWrap(err error) error                { return err }
+func (testErrorFormatter) ErrNoWrap(err error) error                  { return err }
+func (testErrorFormatter) ErrorOnly(err error, _ ...string) error     { return err }
+func (testErrorFormatter) Error(err error, _ ...string) error         { return err }
+func (testErrorFormatter) Errorf(err error, _ string, _ ...any) error { return err }
+func (testErrorFormatter) NewError(details ...string) error           { return errors.New(fmt.Sprint(details)) }
+func (testErrorFormatter) NewErrorf(format string, args ...any) error {
+	return fmt.Errorf(format, args...)
+}
+
+type testConfig struct {
+	*healthcheck.HealthcheckHTTPConfig
+}
+
+func (testConfig) IsDebug() bool { return false }
+
+type testUnit struct {
+	name string
+	err  string
+}
+
+func (u *testUnit) GetName() string {
+	return u.name
+}
+
+func (u *testUnit) IsHealed(ctx context.Context) bool {
+	return u.Check(ctx).IsHealthy()
+}
+
+func (u *testUnit) Check(_ context.Context) *healthcheck.CheckResult {
+	//nolint:exhaustruct // it's ok in test
+	result := &healthcheck.CheckResult{
+		Name:   u.name,
+		Status: healthcheck.CheckStatusPass,
+	}
+
+	if u.err != "" {
+		result.Status = healthcheck.CheckStatusFail
+		result.Error = u.err
+	}
+
+	return result
+}
+
+func TestTracerSpans(t *testing.T) {
+	//nolint:exhaustruct // it's ok in test, optional features disabled
+	cfg := testConfig{HealthcheckHTTPConfig: &healthcheck.HealthcheckHTTPConfig{
+		LivenessHTTPConfig: &healthcheck.LivenessHTTPConfig{
+			HealthCheckLivenessHTTPPath:         "/liveness",
+			HealthCheckLivenessHTTPPort:         0,
+			HealthCheckLivenessHTTPReadTimeout:  time.Second,
+			HealthCheckLivenessHTTPWriteTimeout: time.Second,
+			HealthCheckLivenessEnabled:          true,
+		},
+		ReadinessHTTPConfig: &healthcheck.ReadinessHTTPConfig{},
+		StartupHTTPConfig:   &healthcheck.StartupHTTPConfig{},
+	}}
+
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+
+	healthChecker := healthcheck.NewHTTPHealthChecker(testLogger{}, testErrorFormatter{}, cfg)
+	healthChecker.SetTracer(NewTracer(provider))
+
+	units := []*testUnit{{name: "database", err: ""}, {name: "node", err: "node is syncing"}}
+	for _, unit := range units {
+		err := healthChecker.AddLivenessProbeUnit(unit)
+		if err != nil {
+			t.Fatalf("unable to add probe unit: %s", err)
+		}
+	}
+
+	handler, err := healthChecker.GetProbeHTTPHandler(healthcheck.LivenessProbeIndex)
+	if err != nil {
+		t.Fatalf("unable to get probe handler: %s", err)
+	}
+
+	server := httptest.NewServer(handler)
+
+	//nolint:noctx // it's ok in test
+	resp, err := server.Client().Get(server.URL + "/liveness")
+	if err != nil {
+		t.Fatalf("unable to get probe: %s", err)
+	}
+
+	_ = resp.Body.Close()
+
+	// close waits for completion of request handler, so probe span already ended
+	server.Close()
+
+	if resp.StatusCode != http.StatusTeapot {
+		t.Fatalf("unexpected probe status code: %d", resp.StatusCode)
+	}
+
+	spans := recorder.Ended()
+
+	probeSpans := filterSpans(spans, ProbeSpanName)
+	if len(probeSpans) != 1 {
+		t.Fatalf("expected one probe span, got %d", len(probeSpans))
+	}
+
+	probe := probeSpans[0]
+	assertAttribute(t, probe, ProbeTypeAttr, attribute.StringValue(healthcheck.ProbeNameLiveness))
+	assertAttribute(t, probe, StatusCodeAttr, attribute.IntValue(http.StatusTeapot))
+
+	checkSpans := filterSpans(spans, CheckSpanName)
+	if len(checkSpans) != len(units) {
+		t.Fatalf("expected %d check spans, got %d", len(units), len(checkSpans))
+	}
+
+	for i, check := range checkSpans {
+		unit := units[i]
+
+		if check.Parent().SpanID() != probe.SpanContext().SpanID() {
+			t.Errorf("check span %s is not child of probe span", unit.name)
+		}
+
+		assertAttribute(t, check, ProbeTypeAttr, attribute.StringValue(healthcheck.ProbeNameLiveness))
+		assertAttribute(t, check, CheckNameAttr, attribute.StringValue(unit.name))
+
+		if _, ok := findAttribute(check, CheckDurationAttr); !ok {
+			t.Errorf("check span %s has no duration attribute", unit.name)
+		}
+
+		if unit.err == "" {
+			assertAttribute(t, check, CheckStatusAttr, attribute.StringValue(string(healthcheck.CheckStatusPass)))
+
+			if _, ok := findAttribute(check, CheckErrorAttr); ok {
+				t.Errorf("check span %s of healthy unit has error attribute", unit.name)
+			}
+
+			continue
+		}
+
+		assertAttribute(t, check, CheckStatusAttr, attribute.StringValue(string(healthcheck.CheckStatusFail)))
+		assertAttribute(t, check, CheckErrorAttr, attribute.StringValue(unit.err))
+
+		if check.Status().Code != codes.Error {
+			t.Errorf("check span %s of failed unit has status %s", unit.name, check.Status().Code)
+		}
+	}
+}
+
+func filterSpans(spans []sdktrace.ReadOnlySpan, name string) []sdktrace.ReadOnlySpan {
+	filtered := make([]sdktrace.ReadOnlySpan, 0, len(spans))
+
+	for _, span := range spans {
+		if span.Name() == name {
+			filtered = append(filtered, span)
+		}
+	}
+
+	return filtered
+}
+
+func findAttribute(span sdktrace.ReadOnlySpan, key attribute.Key) (attribute.Value, bool) {
+	for _, attr := range span.Attributes() {
+		if attr.Key == key {
+			return attr.Value, true
+		}
+	}
+
+	return attribute.Value{}, false
+}
+
+func assertAttribute(t *testing.T, span sdktrace.ReadOnlySpan, key attribute.Key, expected attribute.Value) {
+	t.Helper()
+
+	value, ok := findAttribute(span, key)
+	if !ok {
+		t.Errorf("span %s has no attribute %s", span.Name(), key)
+
+		return
+	}
+
+	if value != expected {
+		t.Errorf("span %s attribute %s = %s, expected %s", span.Name(), key, value.Emit(), expected.Emit())
+	}
+}
@@ -0,0 +1,118 @@
+/*
+ *
+ *
+ * MIT NON-AI License
+ *
+ * Copyright (c) 2022-2024 Aleksei Kotelnikov(gudron2s@gmail.com)
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy of the software and associated documentation files (the "Software"),
+ * to deal in the Software without restriction, including without limitation the rights to use, copy, modify, merge, publish, distribute, sublicense,
+ * and/or sell copies of the Software, and to permit persons to whom the Software is furnished to do so, subject to the following conditions.
+ *
+ * The above copyright notice and this permission notice shall be included in all copies or substantial portions of the Software.
+ *
+ * In addition, the following restrictions apply:
+ *
+ * 1. The Software and any modifications made to it may not be used for the purpose of training or improving machine learning algorithms,
+ * including but not limited to artificial intelligence, natural language processing, or data mining. This condition applies to any derivatives,
+ * modifications, or updates based on the Software code. Any usage of the Software in an AI-training dataset is considered a breach of this License.
+ *
+ * 2. The Software may not be included in any dataset used for training or improving machine learning algorithms,
+ * including but not limited to artificial intelligence, natural language processing, or data mining.
+ *
+ * 3. Any person or organization found to be in violation of these restrictions will be subject to legal action and may be held liable
+ * for any damages resulting from such use.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM,
+ * DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE
+ * OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+ *
+ */
+
+package healthcheck
+
+import (
+	"context"
+	"net/http"
+	"sync"
+)
+
+// Tracer - optional tracer of probe requests and check units execution,
+// e.g. OpenTelemetry tracer from otelhealth package
+type Tracer interface {
+	// StartProbeSpan - start span of probe http-request. Returned context passed to check units of probe
+	StartProbeSpan(httpReq *http.Request, probeName string) (context.Context, ProbeSpan)
+	// StartCheckSpan - start child span of check unit execution. Returned context passed to IsHealed function of unit
+	StartCheckSpan(ctx context.Context, probeName, checkName string) (context.Context, CheckSpan)
+}
+
+// ProbeSpan - span of probe http-request
+type ProbeSpan interface {
+	End(statusCode int)
+}
+
+// CheckSpan - span of check unit execution
+type CheckSpan interface {
+	End(result *CheckResult)
+}
+
+// healthTracer - optional instrumentation of probe requests and check units execution.
+// Uses noop tracer until tracer is set
+type healthTracer struct {
+	tracer Tracer
+
+	mu sync.RWMutex
+}
+
+func (t *healthTracer) SetTracer(tracer Tracer) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if tracer == nil {
+		tracer = noopTracer{}
+	}
+
+	t.tracer = tracer
+}
+
+func (t *healthTracer) getTracer() Tracer {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	return t.tracer
+}
+
+func (t *healthTracer) StartProbeSpan(httpReq *http.Request, probeName string) (context.Context, ProbeSpan) {
+	return t.getTracer().StartProbeSpan(httpReq, probeName)
+}
+
+func (t *healthTracer) StartCheckSpan(ctx context.Context, probeName, checkName string) (context.Context, CheckSpan) {
+	return t.getTracer().StartCheckSpan(ctx, probeName, checkName)
+}
+
+type noopTracer struct{}
+
+func (noopTracer) StartProbeSpan(httpReq *http.Request, _ string) (context.Context, ProbeSpan) {
+	return httpReq.Context(), noopProbeSpan{}
+}
+
+func (noopTracer) StartCheckSpan(ctx context.Context, _, _ string) (context.Context, CheckSpan) {
+	return ctx, noopCheckSpan{}
+}
+
+type noopProbeSpan struct{}
+
+func (noopProbeSpan) End(_ int) {}
+
+type noopCheckSpan struct{}
+
+func (noopCheckSpan) End(_ *CheckResult) {}
+
+func newHealthTracer() *healthTracer {
+	return &healthTracer{
+		tracer: noopTracer{},
+
+		mu: sync.RWMutex{},
+	}
+}
@@ -0,0 +1,181 @@
+/*
+ *
+ *
+ * MIT NON-AI License
+ *
+ * Copyright (c) 2022-2024 Aleksei Kotelnikov(gudron2s@gmail.com)
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy of the software and associated documentation files (the "Software"),
+ * to deal in the Software without restriction, including without limitation the rights to use, copy, modify, merge, publish, distribute, sublicense,
+ * and/or sell copies of the Software, and to permit persons to whom the Software is furnished to do so, subject to the following conditions.
+ *
+ * The above copyright notice and this permission notice shall be included in all copies or substantial portions of the Software.
+ *
+ * In addition, the following restrictions apply:
+ *
+ * 1. The Software and any modifications made to it may not be used for the purpose of training or improving machine learning algorithms,
+ * including but not limited to artificial intelligence, natural language processing, or data mining. This condition applies to any derivatives,
+ * modifications, or updates based on the Software code. Any usage of the Software in an AI-training dataset is considered a breach of this License.
+ *
+ * 2. The Software may not be included in any dataset used for training or improving machine learning algorithms,
+ * including but not limited to artificial intelligence, natural language processing, or data mining.
+ *
+ * 3. Any person or organization found to be in violation of these restrictions will be subject to legal action and may be held liable
+ * for any damages resulting from such use.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM,
+ * DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE
+ * OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+ *
+ */
+package healthcheck
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+type fakeSpanContextKey struct{}
+
+// fakeTracer - records started and ended spans, span name stored in context for check of spans nesting
+type fakeTracer struct {
+	spans []string
+
+	mu sync.Mutex
+}
+
+func (f *fakeTracer) record(span string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.spans = append(f.spans, span)
+}
+
+func (f *fakeTracer) StartProbeSpan(httpReq *http.Request, probeName string) (context.Context, ProbeSpan) {
+	f.record("start probe " + probeName)
+
+	return context.WithValue(httpReq.Context(), fakeSpanContextKey{}, probeName), &fakeProbeSpan{tracer: f}
+}
+
+func (f *fakeTracer) StartCheckSpan(ctx context.Context, probeName, checkName string) (context.Context, CheckSpan) {
+	parent, _ := ctx.Value(fakeSpanContextKey{}).(string)
+	f.record("start check " + checkName + " of " + parent)
+
+	return context.WithValue(ctx, fakeSpanContextKey{}, probeName+"/"+checkName), &fakeCheckSpan{tracer: f}
+}
+
+type fakeProbeSpan struct {
+	tracer *fakeTracer
+}
+
+func (s *fakeProbeSpan) End(statusCode int) {
+	s.tracer.record("end probe " + http.StatusText(statusCode))
+}
+
+type fakeCheckSpan struct {
+	tracer *fakeTracer
+}
+
+func (s *fakeCheckSpan) End(result *CheckResult) {
+	s.tracer.record("end check " + result.Name + " " + string(result.Status))
+}
+
+// spanContextProbeUnit - records span of context passed to IsHealed function
+type spanContextProbeUnit struct {
+	name   string
+	healed bool
+
+	spanName string
+}
+
+func (u *spanContextProbeUnit) GetName() string {
+	return u.name
+}
+
+func (u *spanContextProbeUnit) IsHealed(ctx context.Context) bool {
+	u.spanName, _ = ctx.Value(fakeSpanContextKey{}).(string)
+
+	return u.healed
+}
+
+func TestTracingMiddleware(t *testing.T) {
+	tracer := newHealthTracer()
+
+	checker := newProbeChecker(ProbeNameLiveness, tracer, &checkUnitParams{HistorySize: 1}) //nolint:exhaustruct // defaults
+	handler := newTracingMiddleware(ProbeNameLiveness, tracer,
+		newHTTPHandler(newDiscardLogger(), checker, newAccessPolicy(newDiscardLogger(), &fakeVerboseConfig{
+			bearerToken:  "",
+			allowedCIDRs: nil,
+		})))
+
+	units := []*spanContextProbeUnit{
+		{name: "database", healed: true, spanName: ""},
+		{name: "node", healed: false, spanName: ""},
+	}
+	for _, unit := range units {
+		if err := checker.AddUnit(unit); err != nil {
+			t.Fatalf("unable to add unit: %s", err)
+		}
+	}
+
+	t.Run("noop tracer by default", func(t *testing.T) {
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/liveness", nil))
+
+		if recorder.Code != http.StatusTeapot {
+			t.Fatalf("expected status code %d, got %d", http.StatusTeapot, recorder.Code)
+		}
+
+		if units[0].spanName != "" {
+			t.Fatalf("unexpected span in context of unit: %s", units[0].spanName)
+		}
+	})
+
+	t.Run("spans of probe request and check units", func(t *testing.T) {
+		fake := &fakeTracer{spans: nil, mu: sync.Mutex{}}
+		tracer.SetTracer(fake)
+
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/liveness", nil))
+
+		expectedSpans := []string{
+			"start probe " + ProbeNameLiveness,
+			"start check database of " + ProbeNameLiveness,
+			"end check database pass",
+			"start check node of " + ProbeNameLiveness,
+			"end check node fail",
+			"end probe " + http.StatusText(http.StatusTeapot),
+		}
+
+		if len(fake.spans) != len(expectedSpans) {
+			t.Fatalf("expected spans %q, got %q", expectedSpans, fake.spans)
+		}
+
+		for i := range expectedSpans {
+			if fake.spans[i] != expectedSpans[i] {
+				t.Fatalf("expected spans %q, got %q", expectedSpans, fake.spans)
+			}
+		}
+
+		for _, unit := range units {
+			if unit.spanName != ProbeNameLiveness+"/"+unit.name {
+				t.Fatalf("expected check span in context of unit %s, got %q", unit.name, unit.spanName)
+			}
+		}
+	})
+
+	t.Run("reset to noop tracer", func(t *testing.T) {
+		tracer.SetTracer(nil)
+
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/liveness", nil))
+
+		if units[0].spanName != "" {
+			t.Fatalf("unexpected span in context of unit: %s", units[0].spanName)
+		}
+	})
+}
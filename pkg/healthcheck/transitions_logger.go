@@ -0,0 +1,169 @@
+/*
+ *
+ *
+ * MIT NON-AI License
+ *
+ * Copyright (c) 2022-2024 Aleksei Kotelnikov(gudron2s@gmail.com)
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy of the software and associated documentation files (the "Software"),
+ * to deal in the Software without restriction, including without limitation the rights to use, copy, modify, merge, publish, distribute, sublicense,
+ * and/or sell copies of the Software, and to permit persons to whom the Software is furnished to do so, subject to the following conditions.
+ *
+ * The above copyright notice and this permission notice shall be included in all copies or substantial portions of the Software.
+ *
+ * In addition, the following restrictions apply:
+ *
+ * 1. The Software and any modifications made to it may not be used for the purpose of training or improving machine learning algorithms,
+ * including but not limited to artificial intelligence, natural language processing, or data mining. This condition applies to any derivatives,
+ * modifications, or updates based on the Software code. Any usage of the Software in an AI-training dataset is considered a breach of this License.
+ *
+ * 2. The Software may not be included in any dataset used for training or improving machine learning algorithms,
+ * including but not limited to artificial intelligence, natural language processing, or data mining.
+ *
+ * 3. Any person or organization found to be in violation of these restrictions will be subject to legal action and may be held liable
+ * for any damages resulting from such use.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM,
+ * DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE
+ * OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+ *
+ */
+
+package healthcheck
+
+import (
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// transitionsLogger - logs check units status transitions, not every probe request.
+// Still failing check unit logged again not often than once per repeat interval
+type transitionsLogger struct {
+	l *slog.Logger
+
+	repeatInterval time.Duration
+
+	lastLoggedAt     map[string]time.Time
+	lastTransitionAt map[string]time.Time
+
+	mu sync.Mutex
+}
+
+// OnCheckResult - implementation of checkResultObserver interface
+func (t *transitionsLogger) OnCheckResult(probeName string, prevResult, result *CheckResult) {
+	var prevStatus CheckStatus
+	var wasFlapping bool
+	if prevResult != nil {
+		prevStatus = prevResult.Status
+		wasFlapping = prevResult.Flapping
+	}
+
+	if wasFlapping != result.Flapping {
+		t.logFlapping(probeName, result)
+	}
+
+	// status transitions of flapping check unit are not logged until check unit become stable
+	if wasFlapping && result.Flapping {
+		return
+	}
+
+	isTransition := prevStatus != result.Status
+	if !isTransition && result.IsHealthy() {
+		return
+	}
+
+	key := makeCheckKey(probeName, result.Name)
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	// first passed execution of check unit, e.g. on application start, is not logged -
+	// only time of transition stored for duration of next status
+	if prevResult == nil && result.Status == CheckStatusPass {
+		t.lastTransitionAt[key] = result.Timestamp
+
+		return
+	}
+
+	if !isTransition {
+		if t.repeatInterval <= 0 || result.Timestamp.Sub(t.lastLoggedAt[key]) < t.repeatInterval {
+			return
+		}
+	}
+
+	t.lastLoggedAt[key] = result.Timestamp
+
+	attrs := []any{
+		slog.String(ProbeTypeTag, probeName),
+		slog.String(UnitNameTag, result.Name),
+		slog.String(PrevStatusTag, string(prevStatus)),
+		slog.String(StatusTag, string(result.Status)),
+		slog.Duration(DurationTag, result.Duration),
+	}
+
+	lastTransitionAt, isExists := t.lastTransitionAt[key]
+	if isExists {
+		// time spent in previous status or time of still failing status
+		attrs = append(attrs, slog.Duration(StatusDurationTag, result.Timestamp.Sub(lastTransitionAt)))
+	}
+
+	if isTransition {
+		t.lastTransitionAt[key] = result.Timestamp
+	}
+
+	if result.Status == CheckStatusWarn {
+		t.l.Warn("healthcheck unit status changed", append(attrs,
+			slog.String(FailureReasonTag, result.Error))...)
+
+		return
+	}
+
+	if result.IsHealthy() {
+		t.l.Info("healthcheck unit status changed", attrs...)
+
+		return
+	}
+
+	attrs = append(attrs, slog.String(FailureReasonTag, result.Error),
+		slog.Uint64(ConsecutiveFailuresTag, result.ConsecutiveFailures))
+
+	if isTransition {
+		t.l.Warn("healthcheck unit status changed", attrs...)
+
+		return
+	}
+
+	t.l.Warn("healthcheck unit still failing", attrs...)
+}
+
+func (t *transitionsLogger) logFlapping(probeName string, result *CheckResult) {
+	attrs := []any{
+		slog.String(ProbeTypeTag, probeName),
+		slog.String(UnitNameTag, result.Name),
+		slog.String(StatusTag, string(result.Status)),
+		slog.Float64(StateChangePercentTag, result.StateChangePercent),
+	}
+
+	if result.Flapping {
+		t.l.Warn("healthcheck unit started flapping", attrs...)
+
+		return
+	}
+
+	t.l.Info("healthcheck unit stopped flapping", attrs...)
+}
+
+func newTransitionsLogger(logger *slog.Logger, repeatInterval time.Duration) *transitionsLogger {
+	return &transitionsLogger{
+		l: logger,
+
+		repeatInterval: repeatInterval,
+
+		lastLoggedAt:     make(map[string]time.Time),
+		lastTransitionAt: make(map[string]time.Time),
+
+		mu: sync.Mutex{},
+	}
+}
@@ -0,0 +1,185 @@
+/*
+ *
+ *
+ * MIT NON-AI License
+ *
+ * Copyright (c) 2022-2024 Aleksei Kotelnikov(gudron2s@gmail.com)
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy of the software and associated documentation files (the "Software"),
+ * to deal in the Software without restriction, including without limitation the rights to use, copy, modify, merge, publish, distribute, sublicense,
+ * and/or sell copies of the Software, and to permit persons to whom the Software is furnished to do so, subject to the following conditions.
+ *
+ * The above copyright notice and this permission notice shall be included in all copies or substantial portions of the Software.
+ *
+ * In addition, the following restrictions apply:
+ *
+ * 1. The Software and any modifications made to it may not be used for the purpose of training or improving machine learning algorithms,
+ * including but not limited to artificial intelligence, natural language processing, or data mining. This condition applies to any derivatives,
+ * modifications, or updates based on the Software code. Any usage of the Software in an AI-training dataset is considered a breach of this License.
+ *
+ * 2. The Software may not be included in any dataset used for training or improving machine learning algorithms,
+ * including but not limited to artificial intelligence, natural language processing, or data mining.
+ *
+ * 3. Any person or organization found to be in violation of these restrictions will be subject to legal action and may be held liable
+ * for any damages resulting from such use.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM,
+ * DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE
+ * OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+ *
+ */
+package healthcheck
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+)
+
+// newRecordingLogger - logger which writes records as JSON lines into returned buffer
+func newRecordingLogger() (*slog.Logger, *bytes.Buffer) {
+	buffer := &bytes.Buffer{}
+
+	return slog.New(slog.NewJSONHandler(buffer, nil)), buffer
+}
+
+// readLoggedMessages - returns messages of JSON log records in order of logging
+func readLoggedMessages(t *testing.T, buffer *bytes.Buffer) []string {
+	t.Helper()
+
+	messages := make([]string, 0)
+
+	for _, line := range strings.Split(strings.TrimSpace(buffer.String()), "\n") {
+		if line == "" {
+			continue
+		}
+
+		record := make(map[string]any)
+		if err := json.Unmarshal([]byte(line), &record); err != nil {
+			t.Fatalf("unable to unmarshal log record: %s", err)
+		}
+
+		messages = append(messages, record[slog.MessageKey].(string)) //nolint:forcetypeassert // test
+	}
+
+	return messages
+}
+
+func TestTransitionsLogger_OnCheckResult(t *testing.T) {
+	startedAt := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	type execution struct {
+		offset time.Duration
+		status CheckStatus
+	}
+
+	testCases := []struct {
+		name             string
+		repeatInterval   time.Duration
+		executions       []execution
+		expectedMessages []string
+	}{
+		{
+			name:           "first passed execution not logged",
+			repeatInterval: time.Minute,
+			executions: []execution{
+				{offset: 0, status: CheckStatusPass},
+				{offset: time.Second, status: CheckStatusPass},
+			},
+			expectedMessages: []string{},
+		},
+		{
+			name:           "still failing unit suppressed within repeat interval",
+			repeatInterval: 10 * time.Second,
+			executions: []execution{
+				{offset: 0, status: CheckStatusPass},
+				{offset: time.Second, status: CheckStatusFail},
+				{offset: 2 * time.Second, status: CheckStatusFail},
+				{offset: 10 * time.Second, status: CheckStatusFail},
+				{offset: 11 * time.Second, status: CheckStatusFail},
+				{offset: 12 * time.Second, status: CheckStatusFail},
+				{offset: 13 * time.Second, status: CheckStatusPass},
+			},
+			expectedMessages: []string{
+				"healthcheck unit status changed",
+				"healthcheck unit still failing",
+				"healthcheck unit status changed",
+			},
+		},
+		{
+			name:           "still failing unit not repeated without repeat interval",
+			repeatInterval: 0,
+			executions: []execution{
+				{offset: 0, status: CheckStatusFail},
+				{offset: time.Hour, status: CheckStatusFail},
+				{offset: 2 * time.Hour, status: CheckStatusWarn},
+			},
+			expectedMessages: []string{
+				"healthcheck unit status changed",
+				"healthcheck unit status changed",
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			logger, buffer := newRecordingLogger()
+			transitionsLog := newTransitionsLogger(logger, testCase.repeatInterval)
+
+			var prevResult *CheckResult
+
+			for _, exec := range testCase.executions {
+				result := NewCheckResult("database")
+				result.Status = exec.status
+				result.Timestamp = startedAt.Add(exec.offset)
+
+				transitionsLog.OnCheckResult(ProbeNameLiveness, prevResult, result)
+
+				prevResult = result
+			}
+
+			messages := readLoggedMessages(t, buffer)
+			if strings.Join(messages, "\n") != strings.Join(testCase.expectedMessages, "\n") {
+				t.Fatalf("expected messages %q, got %q", testCase.expectedMessages, messages)
+			}
+		})
+	}
+}
+
+func TestTransitionsLogger_OnCheckResult_Flapping(t *testing.T) {
+	logger, buffer := newRecordingLogger()
+	transitionsLog := newTransitionsLogger(logger, time.Minute)
+
+	statuses := []CheckStatus{CheckStatusPass, CheckStatusFail, CheckStatusPass, CheckStatusFail, CheckStatusPass}
+	flapping := []bool{false, false, true, true, false}
+
+	var prevResult *CheckResult
+
+	for i, status := range statuses {
+		result := NewCheckResult("database")
+		result.Status = status
+		result.Flapping = flapping[i]
+		result.Timestamp = time.Date(2024, 1, 1, 0, 0, i, 0, time.UTC)
+
+		transitionsLog.OnCheckResult(ProbeNameLiveness, prevResult, result)
+
+		prevResult = result
+	}
+
+	expectedMessages := []string{
+		"healthcheck unit status changed",
+		"healthcheck unit started flapping",
+		"healthcheck unit status changed",
+		"healthcheck unit stopped flapping",
+		"healthcheck unit status changed",
+	}
+
+	messages := readLoggedMessages(t, buffer)
+	if strings.Join(messages, "\n") != strings.Join(expectedMessages, "\n") {
+		t.Fatalf("expected messages %q, got %q", expectedMessages, messages)
+	}
+}